@@ -0,0 +1,136 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+type PeriodUnit string
+
+const (
+	Day   PeriodUnit = "day"
+	Week  PeriodUnit = "week"
+	Month PeriodUnit = "month"
+	Year  PeriodUnit = "year"
+)
+
+const (
+	StatusActive   string = "active"
+	StatusPastDue  string = "past_due"
+	StatusUnpaid   string = "unpaid"
+	StatusCanceled string = "canceled"
+)
+
+type Period struct {
+	Unit  PeriodUnit
+	Count int
+}
+
+type Plan struct {
+	Id          string
+	Name        string
+	Amount      *yandex.Amount
+	Period      Period
+	Description string
+	Receipt     *yandex.Receipt
+}
+
+type Subscription struct {
+	Id                 string
+	PlanId             string
+	CustomerId         string
+	PaymentMethodId    string
+	Status             string
+	BillingAnchor      time.Time
+	Cycle              int
+	CurrentPeriodStart time.Time
+	NextBillingAt      time.Time
+	RetryAt            time.Time
+	Attempt            int
+	PendingPaymentId   string
+	LastPaymentId      string
+	LastReason         string
+	Metadata           map[string]interface{}
+}
+
+type Storage interface {
+	DueSubscriptions(now time.Time) ([]*Subscription, error)
+	GetPlan(id string) (*Plan, error)
+	SaveSubscription(sub *Subscription) error
+}
+
+type Client interface {
+	yandex.Payments
+	yandex.SavedPaymentMethods
+}
+
+func Monthly() Period {
+	return Period{Unit: Month, Count: 1}
+}
+
+func Yearly() Period {
+	return Period{Unit: Year, Count: 1}
+}
+
+func (p Period) Next(t time.Time) time.Time {
+	return p.At(t, 1)
+}
+
+// At returns the start of the n-th period counted from anchor. Months are
+// always added to the anchor, so a subscription started on 31 Jan is billed
+// on 29 Feb and on 31 Mar again instead of drifting to the 29th.
+func (p Period) At(anchor time.Time, n int) time.Time {
+	count := p.Count
+
+	if count <= 0 {
+		count = 1
+	}
+
+	switch p.Unit {
+	case Day:
+		return anchor.AddDate(0, 0, count*n)
+	case Week:
+		return anchor.AddDate(0, 0, 7*count*n)
+	case Year:
+		count *= 12
+	}
+
+	y, m, d := anchor.Date()
+	first := time.Date(y, m+time.Month(count*n), 1, anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), anchor.Location())
+
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+
+	return first.AddDate(0, 0, d-1)
+}
+
+// Advance moves the subscription to the next period computed from its
+// billing anchor, which defaults to the current billing date.
+func (s *Subscription) Advance(p Period) {
+	if s.BillingAnchor.IsZero() {
+		s.BillingAnchor = s.NextBillingAt
+		s.Cycle = 0
+	}
+
+	s.Cycle++
+	s.CurrentPeriodStart = s.NextBillingAt
+	s.NextBillingAt = p.At(s.BillingAnchor, s.Cycle)
+}
+
+func (s *Subscription) DueAt() time.Time {
+	if !s.RetryAt.IsZero() {
+		return s.RetryAt
+	}
+
+	return s.NextBillingAt
+}
+
+func (s *Subscription) IsDue(now time.Time) bool {
+	if s.Status != StatusActive && s.Status != StatusPastDue {
+		return false
+	}
+
+	return !s.DueAt().After(now)
+}
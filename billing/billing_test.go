@@ -0,0 +1,95 @@
+package billing
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 10, 30, 0, 0, time.UTC)
+}
+
+func TestPeriodAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		period Period
+		anchor time.Time
+		n      int
+		want   time.Time
+	}{
+		{"month end into leap february", Monthly(), date(2024, time.January, 31), 1, date(2024, time.February, 29)},
+		{"month end into february", Monthly(), date(2023, time.January, 31), 1, date(2023, time.February, 28)},
+		{"back to month end after february", Monthly(), date(2024, time.January, 31), 2, date(2024, time.March, 31)},
+		{"thirty day month", Monthly(), date(2024, time.January, 31), 3, date(2024, time.April, 30)},
+		{"next year february", Monthly(), date(2024, time.January, 31), 13, date(2025, time.February, 28)},
+		{"december into january", Monthly(), date(2023, time.December, 31), 1, date(2024, time.January, 31)},
+		{"december into leap february", Monthly(), date(2023, time.December, 31), 2, date(2024, time.February, 29)},
+		{"mid month", Monthly(), date(2024, time.January, 15), 1, date(2024, time.February, 15)},
+		{"quarter from november", Period{Unit: Month, Count: 3}, date(2023, time.November, 30), 1, date(2024, time.February, 29)},
+		{"leap day yearly", Yearly(), date(2024, time.February, 29), 1, date(2025, time.February, 28)},
+		{"leap day after four years", Yearly(), date(2024, time.February, 29), 4, date(2028, time.February, 29)},
+		{"zero count", Period{Unit: Month}, date(2024, time.March, 31), 1, date(2024, time.April, 30)},
+		{"days", Period{Unit: Day, Count: 10}, date(2024, time.February, 25), 1, date(2024, time.March, 6)},
+		{"weeks", Period{Unit: Week, Count: 2}, date(2023, time.December, 25), 1, date(2024, time.January, 8)},
+		{"start", Monthly(), date(2024, time.January, 31), 0, date(2024, time.January, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.At(tt.anchor, tt.n); !got.Equal(tt.want) {
+				t.Errorf("At() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionAdvance(t *testing.T) {
+	sub := &Subscription{NextBillingAt: date(2024, time.January, 31)}
+	want := []time.Time{
+		date(2024, time.February, 29),
+		date(2024, time.March, 31),
+		date(2024, time.April, 30),
+		date(2024, time.May, 31),
+	}
+
+	prev := sub.NextBillingAt
+
+	for i, w := range want {
+		sub.Advance(Monthly())
+
+		if !sub.NextBillingAt.Equal(w) || !sub.CurrentPeriodStart.Equal(prev) {
+			t.Errorf("period %d: %s - %s, want %s - %s", i+1, sub.CurrentPeriodStart, sub.NextBillingAt, prev, w)
+		}
+
+		prev = w
+	}
+
+	if !sub.BillingAnchor.Equal(date(2024, time.January, 31)) || sub.Cycle != len(want) {
+		t.Errorf("anchor %s, cycle %d", sub.BillingAnchor, sub.Cycle)
+	}
+}
+
+func TestSubscriptionIsDue(t *testing.T) {
+	now := date(2024, time.March, 1)
+
+	tests := []struct {
+		name string
+		sub  *Subscription
+		due  bool
+	}{
+		{"due", &Subscription{Status: StatusActive, NextBillingAt: now}, true},
+		{"not yet", &Subscription{Status: StatusActive, NextBillingAt: now.Add(time.Second)}, false},
+		{"retry later", &Subscription{Status: StatusPastDue, NextBillingAt: now, RetryAt: now.Add(time.Hour)}, false},
+		{"retry now", &Subscription{Status: StatusPastDue, NextBillingAt: now, RetryAt: now}, true},
+		{"unpaid", &Subscription{Status: StatusUnpaid, NextBillingAt: now}, false},
+		{"canceled", &Subscription{Status: StatusCanceled, NextBillingAt: now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if due := tt.sub.IsDue(now); due != tt.due {
+				t.Errorf("IsDue() = %t, want %t", due, tt.due)
+			}
+		})
+	}
+}
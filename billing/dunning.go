@@ -0,0 +1,50 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+type DunningSchedule struct {
+	Delays   []time.Duration
+	Reasons  map[string][]time.Duration
+	Terminal map[string]bool
+}
+
+func DefaultDunning() *DunningSchedule {
+	return &DunningSchedule{
+		Delays: []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour},
+		Reasons: map[string][]time.Duration{
+			yandex.CancellationReasonInsufficientFunds: {24 * time.Hour, 2 * 24 * time.Hour, 4 * 24 * time.Hour, 7 * 24 * time.Hour},
+			yandex.CancellationReasonIssuerUnavailable: {time.Hour, 6 * time.Hour, 24 * time.Hour},
+			yandex.CancellationReasonInternalTimeout:   {time.Hour, 6 * time.Hour, 24 * time.Hour},
+		},
+		Terminal: map[string]bool{
+			yandex.CancellationReasonPermissionRevoked:       true,
+			yandex.CancellationReasonCardExpired:             true,
+			yandex.CancellationReasonFraudSuspected:          true,
+			yandex.CancellationReasonInvalidCardNumber:       true,
+			yandex.CancellationReasonPaymentMethodRestricted: true,
+			yandex.CancellationReasonCountryForbidden:        true,
+		},
+	}
+}
+
+func (d *DunningSchedule) Next(reason string, attempt int) (time.Duration, bool) {
+	if d.Terminal[reason] {
+		return 0, false
+	}
+
+	delays, ok := d.Reasons[reason]
+
+	if !ok {
+		delays = d.Delays
+	}
+
+	if attempt < 0 || attempt >= len(delays) {
+		return 0, false
+	}
+
+	return delays[attempt], true
+}
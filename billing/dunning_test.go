@@ -0,0 +1,41 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func TestDunningNext(t *testing.T) {
+	d := DefaultDunning()
+
+	tests := []struct {
+		name    string
+		reason  string
+		attempt int
+		delay   time.Duration
+		ok      bool
+	}{
+		{"default first", yandex.CancellationReasonGeneralDecline, 0, 24 * time.Hour, true},
+		{"default last", yandex.CancellationReasonGeneralDecline, 2, 5 * 24 * time.Hour, true},
+		{"default exhausted", yandex.CancellationReasonGeneralDecline, 3, 0, false},
+		{"unknown reason", "new_reason", 1, 3 * 24 * time.Hour, true},
+		{"insufficient funds", yandex.CancellationReasonInsufficientFunds, 3, 7 * 24 * time.Hour, true},
+		{"insufficient funds exhausted", yandex.CancellationReasonInsufficientFunds, 4, 0, false},
+		{"issuer unavailable", yandex.CancellationReasonIssuerUnavailable, 0, time.Hour, true},
+		{"permission revoked", yandex.CancellationReasonPermissionRevoked, 0, 0, false},
+		{"card expired", yandex.CancellationReasonCardExpired, 0, 0, false},
+		{"negative attempt", yandex.CancellationReasonGeneralDecline, -1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := d.Next(tt.reason, tt.attempt)
+
+			if delay != tt.delay || ok != tt.ok {
+				t.Errorf("Next() = %s, %t, want %s, %t", delay, ok, tt.delay, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package billing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MemoryStorage struct {
+	mu            sync.Mutex
+	plans         map[string]*Plan
+	subscriptions map[string]*Subscription
+}
+
+var _ Storage = (*MemoryStorage)(nil)
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		plans:         map[string]*Plan{},
+		subscriptions: map[string]*Subscription{},
+	}
+}
+
+func (m *MemoryStorage) SavePlan(plan *Plan) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.plans[plan.Id] = plan
+}
+
+func (m *MemoryStorage) GetPlan(id string) (*Plan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	plan, ok := m.plans[id]
+
+	if !ok {
+		return nil, fmt.Errorf("billing: plan %s not found", id)
+	}
+
+	return plan, nil
+}
+
+func (m *MemoryStorage) GetSubscription(id string) (*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sub, ok := m.subscriptions[id]
+
+	if !ok {
+		return nil, fmt.Errorf("billing: subscription %s not found", id)
+	}
+
+	res := *sub
+
+	return &res, nil
+}
+
+func (m *MemoryStorage) SaveSubscription(sub *Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := *sub
+	m.subscriptions[sub.Id] = &res
+
+	return nil
+}
+
+func (m *MemoryStorage) DueSubscriptions(now time.Time) ([]*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := []*Subscription{}
+
+	for _, sub := range m.subscriptions {
+		if sub.IsDue(now) {
+			s := *sub
+			res = append(res, &s)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].DueAt().Before(res[j].DueAt())
+	})
+
+	return res, nil
+}
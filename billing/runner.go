@@ -0,0 +1,151 @@
+package billing
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pantuchy/yandex-go"
+)
+
+// DefaultPendingPoll is how long a subscription with a pending payment
+// waits before the payment status is checked again.
+const DefaultPendingPoll time.Duration = time.Hour
+
+var namespace = uuid.Must(uuid.FromString("5b0f3ab4-7bd1-4c1e-9b39-0c6c2b2f4e6a"))
+
+type Runner struct {
+	Client      Client
+	Storage     Storage
+	Dunning     *DunningSchedule
+	Now         func() time.Time
+	PendingPoll time.Duration
+}
+
+type Result struct {
+	Subscription *Subscription
+	Payment      *yandex.Payment
+	Err          error
+}
+
+func NewRunner(client Client, storage Storage) *Runner {
+	return &Runner{
+		Client:      client,
+		Storage:     storage,
+		Dunning:     DefaultDunning(),
+		Now:         time.Now,
+		PendingPoll: DefaultPendingPoll,
+	}
+}
+
+// IdempotenceKey is stable for a subscription, billing period and dunning
+// attempt, so a crashed run repeated later can't charge the customer twice.
+func IdempotenceKey(sub *Subscription) string {
+	name := sub.Id + "|" + sub.NextBillingAt.UTC().Format(time.RFC3339) + "|" + strconv.Itoa(sub.Attempt)
+
+	return uuid.NewV5(namespace, name).String()
+}
+
+func (r *Runner) Run() ([]*Result, error) {
+	subs, err := r.Storage.DueSubscriptions(r.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Result{}
+
+	for _, sub := range subs {
+		res = append(res, r.Charge(sub))
+	}
+
+	return res, nil
+}
+
+func (r *Runner) Charge(sub *Subscription) *Result {
+	res := &Result{
+		Subscription: sub,
+	}
+
+	plan, err := r.Storage.GetPlan(sub.PlanId)
+
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if len(sub.PendingPaymentId) > 0 {
+		res.Payment, res.Err = r.Client.GetPaymentInfo(sub.PendingPaymentId)
+	} else {
+		req := &yandex.PaymentRequest{
+			Amount:      plan.Amount,
+			Description: plan.Description,
+			Receipt:     plan.Receipt,
+			Metadata: map[string]interface{}{
+				"subscription_id": sub.Id,
+				"billing_period":  sub.NextBillingAt.UTC().Format(time.RFC3339),
+			},
+		}
+
+		res.Payment, res.Err = r.Client.ChargeSavedPaymentMethod(IdempotenceKey(sub), sub.PaymentMethodId, req)
+	}
+
+	if res.Err != nil && !errors.Is(res.Err, yandex.ErrPermissionRevoked) {
+		return res
+	}
+
+	r.apply(sub, plan, res.Payment)
+
+	if err := r.Storage.SaveSubscription(sub); err != nil {
+		log.Printf("Failed saving subscription %s: %v\n", sub.Id, err)
+		res.Err = err
+	}
+
+	return res
+}
+
+func (r *Runner) apply(sub *Subscription, plan *Plan, p *yandex.Payment) {
+	switch p.Status {
+	case yandex.PaymentStatusSucceeded:
+		sub.Status = StatusActive
+		sub.Advance(plan.Period)
+		sub.RetryAt = time.Time{}
+		sub.Attempt = 0
+		sub.PendingPaymentId = ""
+		sub.LastPaymentId = p.Id
+		sub.LastReason = ""
+	case yandex.PaymentStatusCanceled:
+		reason := ""
+
+		if p.CancellationDetails != nil {
+			reason = p.CancellationDetails.Reason
+		}
+
+		sub.PendingPaymentId = ""
+		sub.LastPaymentId = p.Id
+		sub.LastReason = reason
+
+		delay, ok := r.Dunning.Next(reason, sub.Attempt)
+
+		if !ok {
+			sub.Status = StatusUnpaid
+			sub.RetryAt = time.Time{}
+			return
+		}
+
+		sub.Status = StatusPastDue
+		sub.Attempt++
+		sub.RetryAt = r.Now().Add(delay)
+	default:
+		poll := r.PendingPoll
+
+		if poll <= 0 {
+			poll = DefaultPendingPoll
+		}
+
+		sub.PendingPaymentId = p.Id
+		sub.RetryAt = r.Now().Add(poll)
+	}
+}
@@ -0,0 +1,149 @@
+package billing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/pantuchy/yandex-go/yandextest"
+	"github.com/shopspring/decimal"
+)
+
+func newTestRunner(m *yandextest.Mock, now time.Time) (*Runner, *MemoryStorage) {
+	storage := NewMemoryStorage()
+
+	storage.SavePlan(&Plan{
+		Id:     "monthly",
+		Amount: &yandex.Amount{Value: decimal.NewFromInt(299), Currency: "RUB"},
+		Period: Monthly(),
+	})
+
+	storage.SaveSubscription(&Subscription{
+		Id:              "s1",
+		PlanId:          "monthly",
+		PaymentMethodId: "pm1",
+		Status:          StatusActive,
+		NextBillingAt:   date(2024, time.January, 31),
+	})
+
+	r := NewRunner(m, storage)
+	r.Now = func() time.Time { return now }
+
+	return r, storage
+}
+
+func canceledPayment(reason string) *yandex.Payment {
+	return &yandex.Payment{
+		Id:     "p1",
+		Status: yandex.PaymentStatusCanceled,
+		CancellationDetails: &yandex.CancellationDetails{
+			Party:  yandex.CancellationPartyPaymentNetwork,
+			Reason: reason,
+		},
+	}
+}
+
+func TestRunnerCharge(t *testing.T) {
+	now := date(2024, time.January, 31)
+
+	tests := []struct {
+		name    string
+		payment *yandex.Payment
+		err     error
+		status  string
+		next    time.Time
+		retryAt time.Time
+		attempt int
+	}{
+		{"succeeded", &yandex.Payment{Id: "p1", Status: yandex.PaymentStatusSucceeded}, nil, StatusActive, date(2024, time.February, 29), time.Time{}, 0},
+		{"declined", canceledPayment(yandex.CancellationReasonInsufficientFunds), nil, StatusPastDue, now, now.Add(24 * time.Hour), 1},
+		{"terminal decline", canceledPayment(yandex.CancellationReasonCardExpired), nil, StatusUnpaid, now, time.Time{}, 0},
+		{"permission revoked", canceledPayment(yandex.CancellationReasonPermissionRevoked), yandex.ErrPermissionRevoked, StatusUnpaid, now, time.Time{}, 0},
+		{"request failed", nil, errors.New("connection reset"), StatusActive, now, time.Time{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &yandextest.Mock{
+				ChargeSavedPaymentMethodFunc: func(idempKey, methodId string, req *yandex.PaymentRequest) (*yandex.Payment, error) {
+					return tt.payment, tt.err
+				},
+			}
+
+			r, storage := newTestRunner(m, now)
+			res, err := r.Run()
+
+			if err != nil || len(res) != 1 {
+				t.Fatalf("Run() = %v, %v", res, err)
+			}
+
+			if res[0].Err != tt.err {
+				t.Errorf("result error = %v, want %v", res[0].Err, tt.err)
+			}
+
+			call := m.LastCall("ChargeSavedPaymentMethod")
+
+			if call == nil || call.Args[0] != IdempotenceKey(&Subscription{Id: "s1", NextBillingAt: now}) || call.Args[1] != "pm1" {
+				t.Errorf("ChargeSavedPaymentMethod() called with %v", call)
+			}
+
+			sub, _ := storage.GetSubscription("s1")
+
+			if sub.Status != tt.status || !sub.NextBillingAt.Equal(tt.next) || !sub.RetryAt.Equal(tt.retryAt) || sub.Attempt != tt.attempt {
+				t.Errorf("subscription %s, next %s, retry %s, attempt %d, want %s, %s, %s, %d", sub.Status, sub.NextBillingAt, sub.RetryAt, sub.Attempt, tt.status, tt.next, tt.retryAt, tt.attempt)
+			}
+		})
+	}
+}
+
+func TestRunnerPendingPayment(t *testing.T) {
+	now := date(2024, time.January, 31)
+	status := yandex.PaymentStatusPending
+
+	m := &yandextest.Mock{
+		ChargeSavedPaymentMethodFunc: func(idempKey, methodId string, req *yandex.PaymentRequest) (*yandex.Payment, error) {
+			return &yandex.Payment{Id: "p1", Status: yandex.PaymentStatusPending}, nil
+		},
+		GetPaymentInfoFunc: func(id string) (*yandex.Payment, error) {
+			return &yandex.Payment{Id: id, Status: status}, nil
+		},
+	}
+
+	r, storage := newTestRunner(m, now)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		status  string
+		charges int
+		polls   int
+	}{
+		{"charged", 0, yandex.PaymentStatusPending, 1, 0},
+		{"same run time", time.Minute, yandex.PaymentStatusPending, 1, 0},
+		{"polled still pending", DefaultPendingPoll, yandex.PaymentStatusPending, 1, 1},
+		{"not polled before next interval", DefaultPendingPoll + time.Minute, yandex.PaymentStatusSucceeded, 1, 1},
+		{"polled succeeded", 2 * DefaultPendingPoll, yandex.PaymentStatusSucceeded, 1, 2},
+	}
+
+	for _, step := range steps {
+		status = step.status
+		r.Now = func() time.Time { return now.Add(step.advance) }
+
+		if _, err := r.Run(); err != nil {
+			t.Fatalf("%s: Run() = %v", step.name, err)
+		}
+
+		charges, polls := len(m.CallsTo("ChargeSavedPaymentMethod")), len(m.CallsTo("GetPaymentInfo"))
+
+		if charges != step.charges || polls != step.polls {
+			t.Errorf("%s: %d charges and %d polls, want %d and %d", step.name, charges, polls, step.charges, step.polls)
+		}
+	}
+
+	sub, _ := storage.GetSubscription("s1")
+
+	if len(sub.PendingPaymentId) > 0 || !sub.RetryAt.IsZero() || !sub.NextBillingAt.Equal(date(2024, time.February, 29)) || sub.LastPaymentId != "p1" {
+		t.Errorf("subscription after payment succeeded: %+v", sub)
+	}
+}
@@ -0,0 +1,75 @@
+package yandex
+
+type Payments interface {
+	CreatePayment(idempKey string, req *PaymentRequest) (*Payment, error)
+	GetPaymentInfo(id string) (*Payment, error)
+	ConfirmPayment(idempKey, id string, req *PaymentConfirmationRequest) (*Payment, error)
+	CancelPayment(idempKey, id string) (*Payment, error)
+}
+
+type SavedPaymentMethods interface {
+	SavePaymentMethod(idempKey string, req *SavePaymentMethodRequest) (*SavedPaymentMethod, error)
+	GetSavedPaymentMethod(id string) (*SavedPaymentMethod, error)
+	ChargeSavedPaymentMethod(idempKey, methodId string, req *PaymentRequest) (*Payment, error)
+}
+
+type Refunds interface {
+	CreateRefund(idempKey string, req *RefundRequest) (*Refund, error)
+	GetRefundInfo(id string) (*Refund, error)
+}
+
+type Receipts interface {
+	CreateReceipt(idempKey string, req *ReceiptRequest) (*Receipt, error)
+	GetReceiptInfo(id string) (*Receipt, error)
+}
+
+type Invoices interface {
+	CreateInvoice(idempKey string, req *InvoiceRequest) (*Invoice, error)
+	GetInvoiceInfo(id string) (*Invoice, error)
+	CancelInvoice(idempKey, id string) (*Invoice, error)
+}
+
+type Deals interface {
+	CreateDeal(idempKey string, req *DealRequest) (*Deal, error)
+	GetDealInfo(id string) (*Deal, error)
+	GetDealsList(req *DealsListRequest) (*DealsListResponse, error)
+}
+
+type Payouts interface {
+	CreatePayout(idempKey string, req *PayoutRequest) (*Payout, error)
+	GetPayoutInfo(id string) (*Payout, error)
+	GetPayoutsList(req *PayoutsListRequest) (*PayoutsListResponse, error)
+}
+
+type PayoutRecipients interface {
+	CreatePersonalData(idempKey string, req *PersonalDataRequest) (*PersonalData, error)
+	GetPersonalData(id string) (*PersonalData, error)
+	GetSBPBanks() (*SBPBanksListResponse, error)
+	CreateSelfEmployed(idempKey string, req *SelfEmployedRequest) (*SelfEmployed, error)
+	GetSelfEmployed(id string) (*SelfEmployed, error)
+}
+
+type Webhooks interface {
+	SubscribeToWebhook(idempKey string, req *Webhook) (*Webhook, error)
+	GetWebhooksList() (*WebhooksListResponse, error)
+	DeleteWebhook(id string) error
+}
+
+type StoreInfo interface {
+	GetStoreInfo() (*Store, error)
+}
+
+type Client interface {
+	Payments
+	SavedPaymentMethods
+	Refunds
+	Receipts
+	Invoices
+	Deals
+	Payouts
+	PayoutRecipients
+	Webhooks
+	StoreInfo
+}
+
+var _ Client = (*Yandex)(nil)
@@ -0,0 +1,293 @@
+package yandex
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+type VATCode uint32
+
+const (
+	VATCodeNoVAT        VATCode = 1
+	VATCode0            VATCode = 2
+	VATCode10           VATCode = 3
+	VATCode20           VATCode = 4
+	VATCode10Calculated VATCode = 5
+	VATCode20Calculated VATCode = 6
+	VATCode5            VATCode = 7
+	VATCode7            VATCode = 8
+	VATCode5Calculated  VATCode = 9
+	VATCode7Calculated  VATCode = 10
+	VATCode22           VATCode = 11
+	VATCode22Calculated VATCode = 12
+)
+
+type TaxSystemCode uint32
+
+const (
+	TaxSystemGeneral           TaxSystemCode = 1
+	TaxSystemSimplified        TaxSystemCode = 2
+	TaxSystemSimplifiedExpense TaxSystemCode = 3
+	TaxSystemImputedIncome     TaxSystemCode = 4
+	TaxSystemAgricultural      TaxSystemCode = 5
+	TaxSystemPatent            TaxSystemCode = 6
+)
+
+type PaymentSubject string
+
+const (
+	PaymentSubjectCommodity                      PaymentSubject = "commodity"
+	PaymentSubjectExcise                         PaymentSubject = "excise"
+	PaymentSubjectJob                            PaymentSubject = "job"
+	PaymentSubjectService                        PaymentSubject = "service"
+	PaymentSubjectGamblingBet                    PaymentSubject = "gambling_bet"
+	PaymentSubjectGamblingPrize                  PaymentSubject = "gambling_prize"
+	PaymentSubjectLottery                        PaymentSubject = "lottery"
+	PaymentSubjectLotteryPrize                   PaymentSubject = "lottery_prize"
+	PaymentSubjectIntellectualActivity           PaymentSubject = "intellectual_activity"
+	PaymentSubjectPayment                        PaymentSubject = "payment"
+	PaymentSubjectAgentCommission                PaymentSubject = "agent_commission"
+	PaymentSubjectPropertyRight                  PaymentSubject = "property_right"
+	PaymentSubjectNonOperatingGain               PaymentSubject = "non_operating_gain"
+	PaymentSubjectInsurancePremium               PaymentSubject = "insurance_premium"
+	PaymentSubjectSalesTax                       PaymentSubject = "sales_tax"
+	PaymentSubjectResortFee                      PaymentSubject = "resort_fee"
+	PaymentSubjectComposite                      PaymentSubject = "composite"
+	PaymentSubjectAnother                        PaymentSubject = "another"
+	PaymentSubjectFine                           PaymentSubject = "fine"
+	PaymentSubjectTax                            PaymentSubject = "tax"
+	PaymentSubjectLien                           PaymentSubject = "lien"
+	PaymentSubjectCost                           PaymentSubject = "cost"
+	PaymentSubjectPensionInsuranceWithoutPayouts PaymentSubject = "pension_insurance_without_payouts"
+	PaymentSubjectPensionInsuranceWithPayouts    PaymentSubject = "pension_insurance_with_payouts"
+	PaymentSubjectHealthInsuranceWithoutPayouts  PaymentSubject = "health_insurance_without_payouts"
+	PaymentSubjectHealthInsuranceWithPayouts     PaymentSubject = "health_insurance_with_payouts"
+	PaymentSubjectHealthInsurance                PaymentSubject = "health_insurance"
+	PaymentSubjectCasino                         PaymentSubject = "casino"
+	PaymentSubjectAgentWithdrawals               PaymentSubject = "agent_withdrawals"
+	PaymentSubjectNonMarkedExcise                PaymentSubject = "non_marked_excise"
+	PaymentSubjectMarkedExcise                   PaymentSubject = "marked_excise"
+	PaymentSubjectMarked                         PaymentSubject = "marked"
+	PaymentSubjectNonMarked                      PaymentSubject = "non_marked"
+)
+
+type PaymentMode string
+
+const (
+	PaymentModeFullPrepayment    PaymentMode = "full_prepayment"
+	PaymentModePartialPrepayment PaymentMode = "partial_prepayment"
+	PaymentModeAdvance           PaymentMode = "advance"
+	PaymentModeFullPayment       PaymentMode = "full_payment"
+	PaymentModePartialPayment    PaymentMode = "partial_payment"
+	PaymentModeCredit            PaymentMode = "credit"
+	PaymentModeCreditPayment     PaymentMode = "credit_payment"
+)
+
+type AgentType string
+
+const (
+	AgentTypeBankingPaymentAgent    AgentType = "banking_payment_agent"
+	AgentTypeBankingPaymentSubagent AgentType = "banking_payment_subagent"
+	AgentTypePaymentAgent           AgentType = "payment_agent"
+	AgentTypePaymentSubagent        AgentType = "payment_subagent"
+	AgentTypeAttorney               AgentType = "attorney"
+	AgentTypeCommissioner           AgentType = "commissioner"
+	AgentTypeAgent                  AgentType = "agent"
+)
+
+type SettlementType string
+
+const (
+	SettlementTypeCashless      SettlementType = "cashless"
+	SettlementTypePrepayment    SettlementType = "prepayment"
+	SettlementTypePostpayment   SettlementType = "postpayment"
+	SettlementTypeConsideration SettlementType = "consideration"
+)
+
+type vatRate struct {
+	Rate        string
+	Description string
+}
+
+var vatRates = map[VATCode]*vatRate{
+	VATCodeNoVAT:        {"0", "Without VAT"},
+	VATCode0:            {"0", "VAT 0%"},
+	VATCode10:           {"0.10", "VAT 10%"},
+	VATCode20:           {"0.20", "VAT 20%"},
+	VATCode10Calculated: {"0.10", "VAT at the calculated rate 10/110"},
+	VATCode20Calculated: {"0.20", "VAT at the calculated rate 20/120"},
+	VATCode5:            {"0.05", "VAT 5%"},
+	VATCode7:            {"0.07", "VAT 7%"},
+	VATCode5Calculated:  {"0.05", "VAT at the calculated rate 5/105"},
+	VATCode7Calculated:  {"0.07", "VAT at the calculated rate 7/107"},
+	VATCode22:           {"0.22", "VAT 22%"},
+	VATCode22Calculated: {"0.22", "VAT at the calculated rate 22/122"},
+}
+
+var taxSystemDescriptions = map[TaxSystemCode]string{
+	TaxSystemGeneral:           "General tax system",
+	TaxSystemSimplified:        "Simplified tax system (income)",
+	TaxSystemSimplifiedExpense: "Simplified tax system (income minus expenses)",
+	TaxSystemImputedIncome:     "Unified tax on imputed income",
+	TaxSystemAgricultural:      "Unified agricultural tax",
+	TaxSystemPatent:            "Patent tax system",
+}
+
+var paymentSubjectDescriptions = map[PaymentSubject]string{
+	PaymentSubjectCommodity:                      "Product",
+	PaymentSubjectExcise:                         "Excise product",
+	PaymentSubjectJob:                            "Work",
+	PaymentSubjectService:                        "Service",
+	PaymentSubjectGamblingBet:                    "Gambling bet",
+	PaymentSubjectGamblingPrize:                  "Gambling prize",
+	PaymentSubjectLottery:                        "Lottery ticket",
+	PaymentSubjectLotteryPrize:                   "Lottery prize",
+	PaymentSubjectIntellectualActivity:           "Results of intellectual activity",
+	PaymentSubjectPayment:                        "Payment",
+	PaymentSubjectAgentCommission:                "Agent commission",
+	PaymentSubjectPropertyRight:                  "Property right",
+	PaymentSubjectNonOperatingGain:               "Non-operating gain",
+	PaymentSubjectInsurancePremium:               "Insurance premium",
+	PaymentSubjectSalesTax:                       "Sales tax",
+	PaymentSubjectResortFee:                      "Resort fee",
+	PaymentSubjectComposite:                      "Several payment subjects",
+	PaymentSubjectAnother:                        "Another payment subject",
+	PaymentSubjectFine:                           "Fine",
+	PaymentSubjectTax:                            "Tax",
+	PaymentSubjectLien:                           "Lien",
+	PaymentSubjectCost:                           "Cost",
+	PaymentSubjectPensionInsuranceWithoutPayouts: "Pension insurance contributions of individual entrepreneurs",
+	PaymentSubjectPensionInsuranceWithPayouts:    "Pension insurance contributions",
+	PaymentSubjectHealthInsuranceWithoutPayouts:  "Health insurance contributions of individual entrepreneurs",
+	PaymentSubjectHealthInsuranceWithPayouts:     "Health insurance contributions",
+	PaymentSubjectHealthInsurance:                "Social insurance contributions",
+	PaymentSubjectCasino:                         "Casino payment",
+	PaymentSubjectAgentWithdrawals:               "Issuing cash by a payment agent",
+	PaymentSubjectNonMarkedExcise:                "Excise product without marking code",
+	PaymentSubjectMarkedExcise:                   "Excise product with marking code",
+	PaymentSubjectMarked:                         "Product with marking code",
+	PaymentSubjectNonMarked:                      "Product without marking code that requires one",
+}
+
+var paymentModeDescriptions = map[PaymentMode]string{
+	PaymentModeFullPrepayment:    "Full prepayment",
+	PaymentModePartialPrepayment: "Partial prepayment",
+	PaymentModeAdvance:           "Advance",
+	PaymentModeFullPayment:       "Full payment",
+	PaymentModePartialPayment:    "Partial payment and loan",
+	PaymentModeCredit:            "Loan",
+	PaymentModeCreditPayment:     "Loan repayment",
+}
+
+var agentTypeDescriptions = map[AgentType]string{
+	AgentTypeBankingPaymentAgent:    "Banking payment agent",
+	AgentTypeBankingPaymentSubagent: "Banking payment subagent",
+	AgentTypePaymentAgent:           "Payment agent",
+	AgentTypePaymentSubagent:        "Payment subagent",
+	AgentTypeAttorney:               "Attorney",
+	AgentTypeCommissioner:           "Commissioner",
+	AgentTypeAgent:                  "Agent",
+}
+
+var settlementTypeDescriptions = map[SettlementType]string{
+	SettlementTypeCashless:      "Cashless payment",
+	SettlementTypePrepayment:    "Prepayment (advance)",
+	SettlementTypePostpayment:   "Postpayment (credit)",
+	SettlementTypeConsideration: "Counter consideration",
+}
+
+func (c VATCode) Valid() bool {
+	_, ok := vatRates[c]
+
+	return ok
+}
+
+func (c VATCode) Description() string {
+	if r, ok := vatRates[c]; ok {
+		return r.Description
+	}
+
+	return ""
+}
+
+// Rate is the VAT rate as a fraction, for example 0.2 for VATCode20.
+func (c VATCode) Rate() decimal.Decimal {
+	if r, ok := vatRates[c]; ok {
+		return decimal.RequireFromString(r.Rate)
+	}
+
+	return decimal.Zero
+}
+
+// Amount returns the VAT included in total. Receipt prices always include
+// VAT, so both the plain and the calculated rates extract it the same way.
+func (c VATCode) Amount(total decimal.Decimal) decimal.Decimal {
+	rate := c.Rate()
+
+	if rate.IsZero() {
+		return decimal.Zero
+	}
+
+	return total.Mul(rate).Div(decimal.NewFromInt(1).Add(rate)).Round(2)
+}
+
+func (c TaxSystemCode) Valid() bool {
+	_, ok := taxSystemDescriptions[c]
+
+	return ok
+}
+
+func (c TaxSystemCode) Description() string {
+	return taxSystemDescriptions[c]
+}
+
+func (s PaymentSubject) Valid() bool {
+	_, ok := paymentSubjectDescriptions[s]
+
+	return ok
+}
+
+func (s PaymentSubject) Description() string {
+	return paymentSubjectDescriptions[s]
+}
+
+func (m PaymentMode) Valid() bool {
+	_, ok := paymentModeDescriptions[m]
+
+	return ok
+}
+
+func (m PaymentMode) Description() string {
+	return paymentModeDescriptions[m]
+}
+
+func (a AgentType) Valid() bool {
+	_, ok := agentTypeDescriptions[a]
+
+	return ok
+}
+
+func (a AgentType) Description() string {
+	return agentTypeDescriptions[a]
+}
+
+func (t SettlementType) Valid() bool {
+	_, ok := settlementTypeDescriptions[t]
+
+	return ok
+}
+
+func (t SettlementType) Description() string {
+	return settlementTypeDescriptions[t]
+}
+
+func (it *Item) Total() decimal.Decimal {
+	if it.Amount == nil {
+		return decimal.Zero
+	}
+
+	return it.Amount.Value.Mul(it.Quantity).Round(2)
+}
+
+func (it *Item) VATAmount() decimal.Decimal {
+	return it.VATCode.Amount(it.Total())
+}
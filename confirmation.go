@@ -0,0 +1,161 @@
+package yandex
+
+const (
+	ConfirmationRedirect          string = "redirect"
+	ConfirmationEmbedded          string = "embedded"
+	ConfirmationQR                string = "qr"
+	ConfirmationExternal          string = "external"
+	ConfirmationMobileApplication string = "mobile_application"
+)
+
+const (
+	LocaleRussian string = "ru_RU"
+	LocaleEnglish string = "en_US"
+)
+
+type ConfirmationDetails interface {
+	ConfirmationType() string
+}
+
+type RedirectConfirmation struct {
+	Enforce         bool
+	ReturnUrl       string
+	ConfirmationUrl string
+}
+
+type EmbeddedConfirmation struct {
+	ConfirmationToken string
+}
+
+type QRConfirmation struct {
+	ConfirmationData string
+}
+
+type ExternalConfirmation struct{}
+
+type MobileApplicationConfirmation struct {
+	ReturnUrl       string
+	ConfirmationUrl string
+}
+
+func (c *RedirectConfirmation) ConfirmationType() string {
+	return ConfirmationRedirect
+}
+
+func (c *EmbeddedConfirmation) ConfirmationType() string {
+	return ConfirmationEmbedded
+}
+
+func (c *QRConfirmation) ConfirmationType() string {
+	return ConfirmationQR
+}
+
+func (c *ExternalConfirmation) ConfirmationType() string {
+	return ConfirmationExternal
+}
+
+func (c *MobileApplicationConfirmation) ConfirmationType() string {
+	return ConfirmationMobileApplication
+}
+
+func NewRedirectConfirmation(returnUrl string) *Confirmation {
+	return &Confirmation{
+		Type:      ConfirmationRedirect,
+		ReturnUrl: returnUrl,
+	}
+}
+
+func NewEmbeddedConfirmation() *Confirmation {
+	return &Confirmation{
+		Type: ConfirmationEmbedded,
+	}
+}
+
+func NewQRConfirmation() *Confirmation {
+	return &Confirmation{
+		Type: ConfirmationQR,
+	}
+}
+
+func NewExternalConfirmation() *Confirmation {
+	return &Confirmation{
+		Type: ConfirmationExternal,
+	}
+}
+
+func NewMobileApplicationConfirmation(returnUrl string) *Confirmation {
+	return &Confirmation{
+		Type:      ConfirmationMobileApplication,
+		ReturnUrl: returnUrl,
+	}
+}
+
+func (c *Confirmation) WithLocale(locale string) *Confirmation {
+	c.Locale = locale
+
+	return c
+}
+
+func (c *Confirmation) WithEnforce() *Confirmation {
+	c.Enforce = true
+
+	return c
+}
+
+func (c *Confirmation) Validate() error {
+	if len(c.Locale) > 0 && c.Locale != LocaleRussian && c.Locale != LocaleEnglish {
+		return newValidationError("confirmation.locale", "Locale must be ru_RU or en_US")
+	}
+
+	if len(c.ConfirmationUrl) > 0 || len(c.ConfirmationToken) > 0 || len(c.ConfirmationData) > 0 {
+		return newValidationError("confirmation", "Confirmation URL, token and data are set by the API and can't be sent")
+	}
+
+	if c.Enforce && c.Type != ConfirmationRedirect {
+		return newValidationError("confirmation.enforce", "Enforce is allowed only for redirect confirmation")
+	}
+
+	switch c.Type {
+	case ConfirmationRedirect, ConfirmationMobileApplication:
+		if len(c.ReturnUrl) == 0 {
+			return newValidationError("confirmation.return_url", "Return URL is required for "+c.Type+" confirmation")
+		}
+	case ConfirmationQR:
+	case ConfirmationEmbedded, ConfirmationExternal:
+		if len(c.ReturnUrl) > 0 {
+			return newValidationError("confirmation.return_url", "Return URL isn't allowed for "+c.Type+" confirmation")
+		}
+	default:
+		return newValidationError("confirmation.type", "Unknown confirmation type "+c.Type)
+	}
+
+	return nil
+}
+
+func (c *Confirmation) Details() (ConfirmationDetails, error) {
+	switch c.Type {
+	case ConfirmationRedirect:
+		return &RedirectConfirmation{
+			Enforce:         c.Enforce,
+			ReturnUrl:       c.ReturnUrl,
+			ConfirmationUrl: c.ConfirmationUrl,
+		}, nil
+	case ConfirmationEmbedded:
+		return &EmbeddedConfirmation{
+			ConfirmationToken: c.ConfirmationToken,
+		}, nil
+	case ConfirmationQR:
+		return &QRConfirmation{
+			ConfirmationData: c.ConfirmationData,
+		}, nil
+	case ConfirmationExternal:
+		return &ExternalConfirmation{}, nil
+	case ConfirmationMobileApplication:
+		return &MobileApplicationConfirmation{
+			ReturnUrl:       c.ReturnUrl,
+			ConfirmationUrl: c.ConfirmationUrl,
+		}, nil
+	}
+
+	return nil, newValidationError("confirmation.type", "Unknown confirmation type "+c.Type)
+}
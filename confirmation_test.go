@@ -0,0 +1,95 @@
+package yandex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfirmationValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     *Confirmation
+		param string
+	}{
+		{"redirect", NewRedirectConfirmation("https://example.com").WithEnforce().WithLocale(LocaleEnglish), ""},
+		{"embedded", NewEmbeddedConfirmation(), ""},
+		{"qr", NewQRConfirmation(), ""},
+		{"external", NewExternalConfirmation().WithLocale(LocaleRussian), ""},
+		{"mobile application", NewMobileApplicationConfirmation("myapp://return"), ""},
+		{"unknown locale", NewEmbeddedConfirmation().WithLocale("de_DE"), "confirmation.locale"},
+		{"response field", &Confirmation{Type: ConfirmationEmbedded, ConfirmationToken: "ct-1"}, "confirmation"},
+		{"enforce not redirect", NewEmbeddedConfirmation().WithEnforce(), "confirmation.enforce"},
+		{"redirect without return url", NewRedirectConfirmation(""), "confirmation.return_url"},
+		{"mobile without return url", NewMobileApplicationConfirmation(""), "confirmation.return_url"},
+		{"external with return url", &Confirmation{Type: ConfirmationExternal, ReturnUrl: "https://example.com"}, "confirmation.return_url"},
+		{"unknown type", &Confirmation{Type: "sms"}, "confirmation.type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.Validate()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("Validate() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestConfirmationDetails(t *testing.T) {
+	tests := []struct {
+		c    *Confirmation
+		want ConfirmationDetails
+	}{
+		{
+			&Confirmation{Type: ConfirmationRedirect, Enforce: true, ReturnUrl: "https://example.com", ConfirmationUrl: "https://pay"},
+			&RedirectConfirmation{Enforce: true, ReturnUrl: "https://example.com", ConfirmationUrl: "https://pay"},
+		},
+		{
+			&Confirmation{Type: ConfirmationEmbedded, ConfirmationToken: "ct-1"},
+			&EmbeddedConfirmation{ConfirmationToken: "ct-1"},
+		},
+		{
+			&Confirmation{Type: ConfirmationQR, ConfirmationData: "https://qr.nspk.ru/1"},
+			&QRConfirmation{ConfirmationData: "https://qr.nspk.ru/1"},
+		},
+		{
+			&Confirmation{Type: ConfirmationExternal},
+			&ExternalConfirmation{},
+		},
+		{
+			&Confirmation{Type: ConfirmationMobileApplication, ReturnUrl: "myapp://return", ConfirmationUrl: "https://pay"},
+			&MobileApplicationConfirmation{ReturnUrl: "myapp://return", ConfirmationUrl: "https://pay"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.c.Type, func(t *testing.T) {
+			d, err := tt.c.Details()
+
+			if err != nil {
+				t.Fatalf("Details() = %v", err)
+			}
+
+			if !reflect.DeepEqual(d, tt.want) {
+				t.Errorf("Details() = %+v, want %+v", d, tt.want)
+			}
+
+			if d.ConfirmationType() != tt.c.Type {
+				t.Errorf("ConfirmationType() = %s, want %s", d.ConfirmationType(), tt.c.Type)
+			}
+		})
+	}
+
+	if _, err := (&Confirmation{Type: "sms"}).Details(); err == nil {
+		t.Error("Details() of unknown type succeeded")
+	}
+}
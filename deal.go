@@ -0,0 +1,223 @@
+package yandex
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	DealTypeSafeDeal string = "safe_deal"
+)
+
+const (
+	DealStatusOpened string = "opened"
+	DealStatusClosed string = "closed"
+)
+
+const (
+	DealFeeMomentPaymentSucceeded string = "payment_succeeded"
+	DealFeeMomentDealClosed       string = "deal_closed"
+)
+
+const (
+	DealSettlementTypePayout string = "payout"
+)
+
+type DealSettlement struct {
+	Type   string  `json:"type"`
+	Amount *Amount `json:"amount"`
+}
+
+type PaymentDeal struct {
+	Id          string            `json:"id"`
+	Settlements []*DealSettlement `json:"settlements"`
+}
+
+type RefundDeal struct {
+	Id                string            `json:"id,omitempty"`
+	RefundSettlements []*DealSettlement `json:"refund_settlements"`
+}
+
+type Deal struct {
+	Type          string                 `json:"type"`
+	Id            string                 `json:"id"`
+	FeeMoment     string                 `json:"fee_moment"`
+	Description   string                 `json:"description,omitempty"`
+	Balance       *Amount                `json:"balance"`
+	PayoutBalance *Amount                `json:"payout_balance"`
+	Status        string                 `json:"status"`
+	CreatedAt     string                 `json:"created_at"`
+	ExpiresAt     string                 `json:"expires_at"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Test          bool                   `json:"test"`
+}
+
+type DealRequest struct {
+	Type        string                 `json:"type"`
+	FeeMoment   string                 `json:"fee_moment"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type DealsListRequest struct {
+	Status         string `url:"status,omitempty"`
+	FullTextSearch string `url:"full_text_search,omitempty"`
+	CreatedAtGte   string `url:"created_at.gte,omitempty"`
+	CreatedAtGt    string `url:"created_at.gt,omitempty"`
+	CreatedAtLte   string `url:"created_at.lte,omitempty"`
+	CreatedAtLt    string `url:"created_at.lt,omitempty"`
+	ExpiresAtGte   string `url:"expires_at.gte,omitempty"`
+	ExpiresAtGt    string `url:"expires_at.gt,omitempty"`
+	ExpiresAtLte   string `url:"expires_at.lte,omitempty"`
+	ExpiresAtLt    string `url:"expires_at.lt,omitempty"`
+	Limit          int    `url:"limit,omitempty"`
+	Cursor         string `url:"cursor,omitempty"`
+}
+
+type DealsListResponse struct {
+	Type       string  `json:"type"`
+	Items      []*Deal `json:"items"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+func (y *Yandex) CreateDeal(idempKey string, req *DealRequest) (*Deal, error) {
+	deal := *req
+
+	if len(deal.Type) == 0 {
+		deal.Type = DealTypeSafeDeal
+	}
+
+	if deal.FeeMoment != DealFeeMomentPaymentSucceeded && deal.FeeMoment != DealFeeMomentDealClosed {
+		return nil, newValidationError("fee_moment", "Fee moment must be payment_succeeded or deal_closed")
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/deals",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.ShopId,
+		SecretKey:      y.SecretKey,
+		IdempotenceKey: idempKey,
+		Body:           &deal,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Deal{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetDealInfo(id string) (*Deal, error) {
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/deals/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Deal{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetDealsList(req *DealsListRequest) (*DealsListResponse, error) {
+	q, err := query.Values(req)
+
+	if err != nil {
+		log.Printf("Failed creating query: %v\n", err)
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/deals",
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
+		Data:       q,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DealsListResponse{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *PaymentRequest) WithDeal(id string, payout *Amount) *PaymentRequest {
+	r.Deal = &PaymentDeal{
+		Id: id,
+		Settlements: []*DealSettlement{
+			{
+				Type:   DealSettlementTypePayout,
+				Amount: payout,
+			},
+		},
+	}
+
+	return r
+}
+
+func (r *RefundRequest) WithDeal(payout *Amount) *RefundRequest {
+	r.Deal = &RefundDeal{
+		RefundSettlements: []*DealSettlement{
+			{
+				Type:   DealSettlementTypePayout,
+				Amount: payout,
+			},
+		},
+	}
+
+	return r
+}
+
+func (d *Deal) IsClosed() bool {
+	return d.Status == DealStatusClosed
+}
+
+func (n *Notification) Deal() (*Deal, error) {
+	res := &Deal{}
+
+	if err := json.Unmarshal(n.Object, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
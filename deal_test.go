@@ -0,0 +1,52 @@
+package yandex
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCreateDeal(t *testing.T) {
+	tests := []struct {
+		name      string
+		feeMoment string
+		ok        bool
+	}{
+		{"payment succeeded", DealFeeMomentPaymentSucceeded, true},
+		{"deal closed", DealFeeMomentDealClosed, true},
+		{"empty", "", false},
+		{"unknown", "payout_succeeded", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &replyDoer{body: `{"id":"dl1","type":"safe_deal","status":"opened"}`}
+			y := &Yandex{HttpClient: d}
+			req := &DealRequest{FeeMoment: tt.feeMoment}
+
+			deal, err := y.CreateDeal("key", req)
+
+			if !tt.ok {
+				if err == nil || d.sent != nil {
+					t.Errorf("CreateDeal() = %v, %v, want validation error", deal, err)
+				}
+
+				return
+			}
+
+			if err != nil || deal.Id != "dl1" {
+				t.Fatalf("CreateDeal() = %v, %v", deal, err)
+			}
+
+			sent := &DealRequest{}
+			json.Unmarshal(d.sent, sent)
+
+			if sent.Type != DealTypeSafeDeal || sent.FeeMoment != tt.feeMoment {
+				t.Errorf("sent %s", d.sent)
+			}
+
+			if len(req.Type) > 0 {
+				t.Errorf("CreateDeal() changed request type to %s", req.Type)
+			}
+		})
+	}
+}
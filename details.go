@@ -0,0 +1,272 @@
+package yandex
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"strings"
+)
+
+type PaymentMethodDetails interface {
+	MethodType() string
+	MethodId() string
+	IsSaved() bool
+}
+
+type PaymentMethodBase struct {
+	Type   string `json:"type"`
+	Id     string `json:"id"`
+	Saved  bool   `json:"saved"`
+	Status string `json:"status,omitempty"`
+	Title  string `json:"title,omitempty"`
+}
+
+type BankCardMethod struct {
+	PaymentMethodBase
+	Card *Card `json:"card,omitempty"`
+}
+
+type SberPayMethod struct {
+	PaymentMethodBase
+	Phone string `json:"phone,omitempty"`
+	Card  *Card  `json:"card,omitempty"`
+}
+
+type TinkoffBankMethod struct {
+	PaymentMethodBase
+	Card *Card `json:"card,omitempty"`
+}
+
+type YooMoneyMethod struct {
+	PaymentMethodBase
+	AccountNumber string `json:"account_number,omitempty"`
+}
+
+type SBPPayerBankDetails struct {
+	BankId string `json:"bank_id"`
+	BIC    string `json:"bic"`
+}
+
+type SBPMethod struct {
+	PaymentMethodBase
+	SbpOperationId   string               `json:"sbp_operation_id,omitempty"`
+	PayerBankDetails *SBPPayerBankDetails `json:"payer_bank_details,omitempty"`
+}
+
+type MobileBalanceMethod struct {
+	PaymentMethodBase
+	Phone string `json:"phone,omitempty"`
+}
+
+type B2BSberbankMethod struct {
+	PaymentMethodBase
+	PaymentPurpose   string            `json:"payment_purpose,omitempty"`
+	VATData          *VATData          `json:"vat_data,omitempty"`
+	PayerBankDetails *PayerBankDetails `json:"payer_bank_details,omitempty"`
+}
+
+type CashMethod struct {
+	PaymentMethodBase
+	Phone string `json:"phone,omitempty"`
+}
+
+type SberLoanMethod struct {
+	PaymentMethodBase
+	LoanOption     string  `json:"loan_option,omitempty"`
+	DiscountAmount *Amount `json:"discount_amount,omitempty"`
+}
+
+type ElectronicCertificateMethod struct {
+	PaymentMethodBase
+	Card                  *Card                  `json:"card,omitempty"`
+	ElectronicCertificate *ElectronicCertificate `json:"electronic_certificate,omitempty"`
+	Articles              []*CertificateArticle  `json:"articles,omitempty"`
+}
+
+type WalletMethod struct {
+	PaymentMethodBase
+	Login         string `json:"login,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	AccountNumber string `json:"account_number,omitempty"`
+}
+
+type UnknownPaymentMethod struct {
+	PaymentMethodBase
+	Raw json.RawMessage `json:"-"`
+}
+
+// paymentMethodFields lists the keys decoded into PaymentMethod fields,
+// the other keys of a response are kept in Extra.
+var paymentMethodFields = jsonFields(reflect.TypeOf(PaymentMethod{}), "payer_bank_details")
+
+var paymentMethodDetails = map[string]func() PaymentMethodDetails{
+	PaymentMethodBankCard:              func() PaymentMethodDetails { return &BankCardMethod{} },
+	PaymentMethodSberbank:              func() PaymentMethodDetails { return &SberPayMethod{} },
+	PaymentMethodTinkoffBank:           func() PaymentMethodDetails { return &TinkoffBankMethod{} },
+	PaymentMethodYooMoney:              func() PaymentMethodDetails { return &YooMoneyMethod{} },
+	PaymentMethodSBP:                   func() PaymentMethodDetails { return &SBPMethod{} },
+	PaymentMethodMobileBalance:         func() PaymentMethodDetails { return &MobileBalanceMethod{} },
+	PaymentMethodB2BSberbank:           func() PaymentMethodDetails { return &B2BSberbankMethod{} },
+	PaymentMethodCash:                  func() PaymentMethodDetails { return &CashMethod{} },
+	PaymentMethodInstallments:          func() PaymentMethodDetails { return &PaymentMethodBase{} },
+	PaymentMethodSberLoan:              func() PaymentMethodDetails { return &SberLoanMethod{} },
+	PaymentMethodSberBNPL:              func() PaymentMethodDetails { return &PaymentMethodBase{} },
+	PaymentMethodElectronicCertificate: func() PaymentMethodDetails { return &ElectronicCertificateMethod{} },
+	PaymentMethodApplePay:              func() PaymentMethodDetails { return &PaymentMethodBase{} },
+	PaymentMethodGooglePay:             func() PaymentMethodDetails { return &PaymentMethodBase{} },
+	PaymentMethodAlfaBank:              func() PaymentMethodDetails { return &WalletMethod{} },
+	PaymentMethodYandexMoney:           func() PaymentMethodDetails { return &WalletMethod{} },
+	PaymentMethodQiwi:                  func() PaymentMethodDetails { return &WalletMethod{} },
+	PaymentMethodWeChat:                func() PaymentMethodDetails { return &WalletMethod{} },
+	PaymentMethodWebmoney:              func() PaymentMethodDetails { return &WalletMethod{} },
+}
+
+func (b *PaymentMethodBase) MethodType() string {
+	return b.Type
+}
+
+func (b *PaymentMethodBase) MethodId() string {
+	return b.Id
+}
+
+func (b *PaymentMethodBase) IsSaved() bool {
+	return b.Saved
+}
+
+func (m *PaymentMethod) UnmarshalJSON(data []byte) error {
+	type plain PaymentMethod
+
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}
+
+	// payer_bank_details has a different shape for b2b_sberbank and sbp,
+	// each is decoded into its own field.
+	payer := struct {
+		Details json.RawMessage `json:"payer_bank_details"`
+	}{}
+
+	if err := json.Unmarshal(data, &payer); err != nil {
+		return err
+	}
+
+	if len(payer.Details) > 0 && string(payer.Details) != "null" {
+		switch m.Type {
+		case PaymentMethodB2BSberbank:
+			m.PayerBankDetails = &PayerBankDetails{}
+
+			if err := json.Unmarshal(payer.Details, m.PayerBankDetails); err != nil {
+				return err
+			}
+		case PaymentMethodSBP:
+			m.SBPPayerBankDetails = &SBPPayerBankDetails{}
+
+			if err := json.Unmarshal(payer.Details, m.SBPPayerBankDetails); err != nil {
+				return err
+			}
+		}
+	}
+
+	fields := map[string]interface{}{}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for k := range fields {
+		if paymentMethodFields[k] {
+			delete(fields, k)
+		}
+	}
+
+	m.Extra = nil
+
+	if len(fields) > 0 {
+		m.Extra = fields
+	}
+
+	m.raw = append(json.RawMessage(nil), data...)
+	m.details = nil
+
+	details, err := m.Details()
+
+	if err != nil {
+		return err
+	}
+
+	m.details = details
+
+	return nil
+}
+
+func (m *PaymentMethod) Raw() json.RawMessage {
+	return m.raw
+}
+
+// Details returns the method decoded into its per-type struct, for
+// responses it's built while the payment is decoded.
+func (m *PaymentMethod) Details() (PaymentMethodDetails, error) {
+	if m.details != nil {
+		return m.details, nil
+	}
+
+	raw := m.raw
+
+	if len(raw) == 0 {
+		bytes, err := json.Marshal(m)
+
+		if err != nil {
+			log.Printf("Failed marshaling struct to bytes: %v\n", err)
+			return nil, err
+		}
+
+		raw = bytes
+	}
+
+	paymentMethodsMu.RLock()
+	factory, ok := paymentMethodDetails[m.Type]
+	paymentMethodsMu.RUnlock()
+
+	var res PaymentMethodDetails
+
+	if ok {
+		res = factory()
+	} else {
+		res = &UnknownPaymentMethod{
+			Raw: raw,
+		}
+	}
+
+	if err := json.Unmarshal(raw, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *Payment) PaymentMethodDetails() (PaymentMethodDetails, error) {
+	if p.PaymentMethod == nil {
+		return nil, nil
+	}
+
+	return p.PaymentMethod.Details()
+}
+
+func jsonFields(t reflect.Type, extra ...string) map[string]bool {
+	res := map[string]bool{}
+
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+
+		if len(name) > 0 && name != "-" {
+			res[name] = true
+		}
+	}
+
+	for _, name := range extra {
+		res[name] = true
+	}
+
+	return res
+}
@@ -0,0 +1,121 @@
+package yandex
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPaymentMethodDecoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		want  PaymentMethodDetails
+		extra map[string]interface{}
+	}{
+		{
+			"bank card",
+			`{"type":"bank_card","id":"pm1","saved":true,"status":"active","title":"Bank card *4444","card":{"first6":"555555","last4":"4444","expiry_year":"2030","expiry_month":"12","card_type":"MasterCard"}}`,
+			&BankCardMethod{
+				PaymentMethodBase: PaymentMethodBase{Type: "bank_card", Id: "pm1", Saved: true, Status: "active", Title: "Bank card *4444"},
+				Card:              &Card{BIN: "555555", LastFourDigits: "4444", ExpiryYear: "2030", ExpiryMonth: "12", CardType: "MasterCard"},
+			},
+			map[string]interface{}{"status": "active"},
+		},
+		{
+			"sbp",
+			`{"type":"sbp","id":"pm2","saved":false,"sbp_operation_id":"1027088AE4CB48CB81287833347A8777","payer_bank_details":{"bank_id":"100000000111","bic":"044525225"}}`,
+			&SBPMethod{
+				PaymentMethodBase: PaymentMethodBase{Type: "sbp", Id: "pm2"},
+				SbpOperationId:    "1027088AE4CB48CB81287833347A8777",
+				PayerBankDetails:  &SBPPayerBankDetails{BankId: "100000000111", BIC: "044525225"},
+			},
+			nil,
+		},
+		{
+			"b2b",
+			`{"type":"b2b_sberbank","id":"pm3","saved":false,"payment_purpose":"Invoice 1","vat_data":{"type":"untaxed"},"payer_bank_details":{"full_name":"OOO Romashka","short_name":"Romashka","address":"Moscow","inn":"7707083893","kpp":"773601001","bank_name":"Sberbank","bank_branch":"Moscow","bank_bik":"044525225","account":"40702810000000000001"}}`,
+			&B2BSberbankMethod{
+				PaymentMethodBase: PaymentMethodBase{Type: "b2b_sberbank", Id: "pm3"},
+				PaymentPurpose:    "Invoice 1",
+				VATData:           &VATData{Type: "untaxed"},
+				PayerBankDetails:  &PayerBankDetails{FullName: "OOO Romashka", ShortName: "Romashka", Address: "Moscow", INN: "7707083893", KPP: "773601001", BankName: "Sberbank", BankBranch: "Moscow", BIC: "044525225", Account: "40702810000000000001"},
+			},
+			nil,
+		},
+		{
+			"sber loan",
+			`{"type":"sber_loan","id":"pm4","saved":false,"loan_option":"loan","discount_amount":{"value":"10.00","currency":"RUB"}}`,
+			&SberLoanMethod{
+				PaymentMethodBase: PaymentMethodBase{Type: "sber_loan", Id: "pm4"},
+				LoanOption:        "loan",
+				DiscountAmount:    rub("10.00"),
+			},
+			map[string]interface{}{"loan_option": "loan", "discount_amount": map[string]interface{}{"value": "10.00", "currency": "RUB"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &PaymentMethod{}
+
+			if err := json.Unmarshal([]byte(tt.json), m); err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+
+			d, err := m.Details()
+
+			if err != nil {
+				t.Fatalf("Details() = %v", err)
+			}
+
+			if !reflect.DeepEqual(d, tt.want) {
+				t.Errorf("Details() = %+v, want %+v", d, tt.want)
+			}
+
+			if !reflect.DeepEqual(m.Extra, tt.extra) {
+				t.Errorf("Extra = %v, want %v", m.Extra, tt.extra)
+			}
+		})
+	}
+}
+
+func TestUnknownPaymentMethod(t *testing.T) {
+	data := `{"type":"crypto_wallet","id":"pm5","saved":false,"wallet":{"network":"ton"}}`
+	p := &Payment{}
+
+	if err := json.Unmarshal([]byte(`{"id":"p1","payment_method":`+data+`}`), p); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+
+	d, err := p.PaymentMethodDetails()
+
+	if err != nil {
+		t.Fatalf("PaymentMethodDetails() = %v", err)
+	}
+
+	u, ok := d.(*UnknownPaymentMethod)
+
+	if !ok || u.MethodType() != "crypto_wallet" || u.MethodId() != "pm5" || string(u.Raw) != data {
+		t.Fatalf("PaymentMethodDetails() = %+v", d)
+	}
+
+	wallet, ok := p.PaymentMethod.Extra["wallet"].(map[string]interface{})
+
+	if !ok || wallet["network"] != "ton" {
+		t.Errorf("Extra = %v", p.PaymentMethod.Extra)
+	}
+
+	b, err := json.Marshal(p.PaymentMethod)
+
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+
+	again := &PaymentMethod{}
+	json.Unmarshal(b, again)
+
+	if !reflect.DeepEqual(again.Extra, p.PaymentMethod.Extra) {
+		t.Errorf("Extra after round trip = %v, want %v", again.Extra, p.PaymentMethod.Extra)
+	}
+}
@@ -1,9 +1,10 @@
 package yandex
 
 type Error struct {
-	Code    int    `json:"code"`
-	ApiCode string `json:"api_code"`
-	Message string `json:"message"`
+	Code      int    `json:"code"`
+	ApiCode   string `json:"api_code"`
+	Message   string `json:"message"`
+	Parameter string `json:"parameter,omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -17,3 +18,16 @@ func (e *Error) GetApiCode() string {
 func (e *Error) GetCode() int {
 	return e.Code
 }
+
+func (e *Error) GetParameter() string {
+	return e.Parameter
+}
+
+func newValidationError(parameter, message string) *Error {
+	return &Error{
+		Code:      400,
+		ApiCode:   "invalid_request",
+		Message:   message,
+		Parameter: parameter,
+	}
+}
@@ -21,10 +21,7 @@ func main() {
 			Value:    price,
 			Currency: "EUR",
 		},
-		Confirmation: &yandex.Confirmation{
-			Type:      "redirect",
-			ReturnUrl: "https://www.merchant-website.com/return_url",
-		},
+		Confirmation: yandex.NewRedirectConfirmation("https://www.merchant-website.com/return_url"),
 	}
 
 	card := &yandex.Card{
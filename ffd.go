@@ -0,0 +1,204 @@
+package yandex
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Measure string
+
+const (
+	MeasurePiece            Measure = "piece"
+	MeasureGram             Measure = "gram"
+	MeasureKilogram         Measure = "kilogram"
+	MeasureTon              Measure = "ton"
+	MeasureCentimeter       Measure = "centimeter"
+	MeasureDecimeter        Measure = "decimeter"
+	MeasureMeter            Measure = "meter"
+	MeasureSquareCentimeter Measure = "square_centimeter"
+	MeasureSquareDecimeter  Measure = "square_decimeter"
+	MeasureSquareMeter      Measure = "square_meter"
+	MeasureMilliliter       Measure = "milliliter"
+	MeasureLiter            Measure = "liter"
+	MeasureCubicMeter       Measure = "cubic_meter"
+	MeasureKilowattHour     Measure = "kilowatt_hour"
+	MeasureGigacalorie      Measure = "gigacalorie"
+	MeasureDay              Measure = "day"
+	MeasureHour             Measure = "hour"
+	MeasureMinute           Measure = "minute"
+	MeasureSecond           Measure = "second"
+	MeasureKilobyte         Measure = "kilobyte"
+	MeasureMegabyte         Measure = "megabyte"
+	MeasureGigabyte         Measure = "gigabyte"
+	MeasureTerabyte         Measure = "terabyte"
+	MeasureAnother          Measure = "another"
+)
+
+// MarkModeDefault is the only marking code processing mode accepted by the
+// fiscal providers, it has to be sent for every item with mark_code_info.
+const MarkModeDefault string = "0"
+
+var (
+	measures = map[Measure]bool{
+		MeasurePiece: true, MeasureGram: true, MeasureKilogram: true, MeasureTon: true,
+		MeasureCentimeter: true, MeasureDecimeter: true, MeasureMeter: true,
+		MeasureSquareCentimeter: true, MeasureSquareDecimeter: true, MeasureSquareMeter: true,
+		MeasureMilliliter: true, MeasureLiter: true, MeasureCubicMeter: true,
+		MeasureKilowattHour: true, MeasureGigacalorie: true,
+		MeasureDay: true, MeasureHour: true, MeasureMinute: true, MeasureSecond: true,
+		MeasureKilobyte: true, MeasureMegabyte: true, MeasureGigabyte: true, MeasureTerabyte: true,
+		MeasureAnother: true,
+	}
+	federalIdPattern = regexp.MustCompile(`^0[0-9]{2}$`)
+)
+
+type MarkQuantity struct {
+	Numerator   uint32 `json:"numerator"`
+	Denominator uint32 `json:"denominator"`
+}
+
+type MarkCodeInfo struct {
+	MarkCodeRaw string `json:"mark_code_raw,omitempty"`
+	Unknown     string `json:"unknown,omitempty"`
+	EAN8        string `json:"ean_8,omitempty"`
+	EAN13       string `json:"ean_13,omitempty"`
+	ITF14       string `json:"itf_14,omitempty"`
+	GS10        string `json:"gs_10,omitempty"`
+	GS1M        string `json:"gs_1m,omitempty"`
+	Short       string `json:"short,omitempty"`
+	Fur         string `json:"fur,omitempty"`
+	EGAIS20     string `json:"egais_20,omitempty"`
+	EGAIS30     string `json:"egais_30,omitempty"`
+}
+
+type IndustryDetails struct {
+	FederalId      string `json:"federal_id"`
+	DocumentDate   string `json:"document_date"`
+	DocumentNumber string `json:"document_number"`
+	Value          string `json:"value"`
+}
+
+type OperationalDetails struct {
+	OperationId uint8  `json:"operation_id"`
+	Value       string `json:"value"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (m Measure) Valid() bool {
+	return measures[m]
+}
+
+func (i *MarkCodeInfo) codes() int {
+	n := 0
+
+	for _, v := range []string{i.Unknown, i.EAN8, i.EAN13, i.ITF14, i.GS10, i.GS1M, i.Short, i.Fur, i.EGAIS20, i.EGAIS30} {
+		if len(v) > 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (it *Item) IsMarked() bool {
+	return it.MarkCodeInfo != nil || len(it.ProductCode) > 0
+}
+
+func (it *Item) validateFFD(prefix string) error {
+	if len(it.Measure) > 0 && !it.Measure.Valid() {
+		return newValidationError(prefix+"items.measure", "Unknown measure "+string(it.Measure))
+	}
+
+	switch it.PaymentSubject {
+	case PaymentSubjectMarked, PaymentSubjectMarkedExcise:
+		if !it.IsMarked() {
+			return newValidationError(prefix+"items.mark_code_info", "Marking code is required for payment subject "+string(it.PaymentSubject))
+		}
+	}
+
+	if it.MarkCodeInfo != nil {
+		if it.MarkCodeInfo.codes() != 1 {
+			return newValidationError(prefix+"items.mark_code_info", "Exactly one marking code must be specified")
+		}
+
+		if it.MarkMode != MarkModeDefault {
+			return newValidationError(prefix+"items.mark_mode", "Mark mode must be 0 for items with marking code")
+		}
+	} else if len(it.MarkMode) > 0 {
+		return newValidationError(prefix+"items.mark_mode", "Mark mode is allowed only for items with marking code")
+	}
+
+	if it.MarkQuantity != nil {
+		if it.MarkCodeInfo == nil {
+			return newValidationError(prefix+"items.mark_quantity", "Mark quantity is allowed only for items with marking code")
+		}
+
+		if it.MarkQuantity.Numerator == 0 || it.MarkQuantity.Numerator >= it.MarkQuantity.Denominator {
+			return newValidationError(prefix+"items.mark_quantity", "Mark quantity must be a proper fraction of the package")
+		}
+
+		if len(it.Measure) > 0 && it.Measure != MeasurePiece {
+			return newValidationError(prefix+"items.measure", "Items sold by mark quantity must be measured in pieces")
+		}
+	} else if it.MarkCodeInfo != nil && !it.Quantity.Equal(decimal.NewFromInt(1)) {
+		// A marking code identifies a single unit, several units are sent as
+		// separate items and a part of one needs mark_quantity.
+		return newValidationError(prefix+"items.quantity", "Quantity of an item with marking code must be 1, send one item per code or set mark quantity to sell a part")
+	}
+
+	for _, d := range it.PaymentSubjectIndustryDetails {
+		if err := d.validate(prefix + "items.payment_subject_industry_details"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *IndustryDetails) validate(param string) error {
+	if !federalIdPattern.MatchString(d.FederalId) {
+		return newValidationError(param+".federal_id", "Federal id must be in 0NN format")
+	}
+
+	if _, err := time.Parse("02.01.2006", d.DocumentDate); err != nil {
+		return newValidationError(param+".document_date", "Document date must be in DD.MM.YYYY format")
+	}
+
+	if len(d.DocumentNumber) == 0 || len([]rune(d.DocumentNumber)) > 32 {
+		return newValidationError(param+".document_number", "Document number must contain 1 to 32 characters")
+	}
+
+	if len(d.Value) == 0 || len([]rune(d.Value)) > 256 {
+		return newValidationError(param+".value", "Value must contain 1 to 256 characters")
+	}
+
+	return nil
+}
+
+func (d *OperationalDetails) validate(param string) error {
+	if len(d.Value) == 0 || len([]rune(d.Value)) > 64 {
+		return newValidationError(param+".value", "Value must contain 1 to 64 characters")
+	}
+
+	if _, err := time.Parse(time.RFC3339, d.CreatedAt); err != nil {
+		return newValidationError(param+".created_at", "Created at must be in ISO 8601 format")
+	}
+
+	return nil
+}
+
+func validateReceiptDetails(industry []*IndustryDetails, operational *OperationalDetails, prefix string) error {
+	for _, d := range industry {
+		if err := d.validate(prefix + "receipt_industry_details"); err != nil {
+			return err
+		}
+	}
+
+	if operational != nil {
+		return operational.validate(prefix + "receipt_operational_details")
+	}
+
+	return nil
+}
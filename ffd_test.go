@@ -0,0 +1,88 @@
+package yandex
+
+import "testing"
+
+func markedItem() *Item {
+	return &Item{
+		Description:    "Shoes",
+		Quantity:       qty("1"),
+		Amount:         rub("5000"),
+		VATCode:        VATCode20,
+		PaymentSubject: PaymentSubjectMarked,
+		Measure:        MeasurePiece,
+		MarkCodeInfo:   &MarkCodeInfo{GS1M: "MDEwNDYwNzQyODc5OTgxMDIxdGVzdA=="},
+		MarkMode:       MarkModeDefault,
+	}
+}
+
+func TestItemValidateFFD(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(it *Item)
+		param  string
+	}{
+		{"valid", nil, ""},
+		{"part of package", func(it *Item) {
+			it.Quantity = qty("0.5")
+			it.MarkQuantity = &MarkQuantity{Numerator: 1, Denominator: 2}
+		}, ""},
+		{"product code only", func(it *Item) {
+			it.MarkCodeInfo, it.MarkMode, it.ProductCode = nil, "", "44 4D 01 04 60 74 28 79 98 10 21 74 65 73 74"
+		}, ""},
+		{"measure", func(it *Item) { it.Measure = "pack" }, "items.measure"},
+		{"no marking code", func(it *Item) { it.MarkCodeInfo, it.MarkMode = nil, "" }, "items.mark_code_info"},
+		{"two codes", func(it *Item) { it.MarkCodeInfo.EAN13 = "4607428799810" }, "items.mark_code_info"},
+		{"no mark mode", func(it *Item) { it.MarkMode = "" }, "items.mark_mode"},
+		{"mark mode without code", func(it *Item) { it.PaymentSubject, it.MarkCodeInfo = PaymentSubjectCommodity, nil }, "items.mark_mode"},
+		{"several units", func(it *Item) { it.Quantity = qty("3") }, "items.quantity"},
+		{"fraction without mark quantity", func(it *Item) { it.Quantity = qty("0.5") }, "items.quantity"},
+		{"mark quantity without code", func(it *Item) {
+			it.PaymentSubject, it.MarkCodeInfo, it.MarkMode = PaymentSubjectCommodity, nil, ""
+			it.MarkQuantity = &MarkQuantity{Numerator: 1, Denominator: 2}
+		}, "items.mark_quantity"},
+		{"improper mark quantity", func(it *Item) { it.MarkQuantity = &MarkQuantity{Numerator: 2, Denominator: 2} }, "items.mark_quantity"},
+		{"mark quantity measure", func(it *Item) {
+			it.Measure = MeasureKilogram
+			it.MarkQuantity = &MarkQuantity{Numerator: 1, Denominator: 2}
+		}, "items.measure"},
+		{"industry details", func(it *Item) {
+			it.PaymentSubjectIndustryDetails = []*IndustryDetails{{FederalId: "30", DocumentDate: "01.02.2024", DocumentNumber: "1", Value: "v"}}
+		}, "items.payment_subject_industry_details.federal_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := markedItem()
+
+			if tt.modify != nil {
+				tt.modify(it)
+			}
+
+			err := it.validateFFD("")
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("validateFFD() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("validateFFD() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestReceiptValidateMarkedItem(t *testing.T) {
+	r := NewReceipt("user@example.com", "")
+	r.Items = append(r.Items, markedItem(), markedItem())
+	r.Items[1].Quantity = qty("2")
+
+	err := r.Validate()
+
+	if e, ok := err.(*Error); !ok || e.Parameter != "receipt.items.quantity" {
+		t.Errorf("Validate() = %v, want error for receipt.items.quantity", err)
+	}
+}
@@ -0,0 +1,243 @@
+package yandex
+
+import (
+	"regexp"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ReceiptTypePayment string = "payment"
+	ReceiptTypeRefund  string = "refund"
+)
+
+const (
+	ReceiptStatusPending   string = "pending"
+	ReceiptStatusSucceeded string = "succeeded"
+	ReceiptStatusCanceled  string = "canceled"
+)
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func NewReceipt(email, phone string) *Receipt {
+	r := &Receipt{}
+
+	if len(email) > 0 || len(phone) > 0 {
+		r.Customer = &Customer{
+			Email: email,
+			Phone: phone,
+		}
+	}
+
+	return r
+}
+
+func (r *Receipt) WithTaxSystem(code TaxSystemCode) *Receipt {
+	r.TaxSystemCode = code
+
+	return r
+}
+
+func (r *Receipt) AddItem(description string, quantity decimal.Decimal, price *Amount, vatCode VATCode) *Receipt {
+	r.Items = append(r.Items, &Item{
+		Description: description,
+		Quantity:    quantity,
+		Amount:      price,
+		VATCode:     vatCode,
+	})
+
+	return r
+}
+
+func (r *Receipt) Total() decimal.Decimal {
+	return itemsTotal(r.Items)
+}
+
+func (r *Receipt) Validate() error {
+	customer := r.Customer
+
+	if customer == nil && (len(r.Email) > 0 || len(r.Phone) > 0) {
+		customer = &Customer{
+			Email: r.Email,
+			Phone: r.Phone,
+		}
+	}
+
+	if err := validateReceipt(customer, r.Items, r.TaxSystemCode, "receipt."); err != nil {
+		return err
+	}
+
+	return validateReceiptDetails(r.ReceiptIndustryDetails, r.ReceiptOperationalDetails, "receipt.")
+}
+
+// ValidateFor checks the receipt sent together with a payment or refund,
+// its items must add up to exactly the amount being charged.
+func (r *Receipt) ValidateFor(amount *Amount) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if amount == nil {
+		return nil
+	}
+
+	for _, it := range r.Items {
+		if it.Amount.Currency != amount.Currency {
+			return newValidationError("receipt.items.amount.currency", "Item currency must match amount currency")
+		}
+	}
+
+	if !r.Total().Equal(amount.Value) {
+		return newValidationError("receipt.items", "Items total "+r.Total().StringFixed(2)+" doesn't match amount "+amount.Value.StringFixed(2))
+	}
+
+	return nil
+}
+
+func NewReceiptRequest(kind, objectId string, receipt *Receipt) *ReceiptRequest {
+	r := &ReceiptRequest{
+		Type:                      kind,
+		Customer:                  receipt.Customer,
+		Items:                     receipt.Items,
+		TaxSystemCode:             receipt.TaxSystemCode,
+		Send:                      true,
+		ReceiptIndustryDetails:    receipt.ReceiptIndustryDetails,
+		ReceiptOperationalDetails: receipt.ReceiptOperationalDetails,
+	}
+
+	if kind == ReceiptTypeRefund {
+		r.RefundId = objectId
+	} else {
+		r.PaymentId = objectId
+	}
+
+	return r
+}
+
+func (r *ReceiptRequest) AddSettlement(kind SettlementType, amount *Amount) *ReceiptRequest {
+	r.Settlements = append(r.Settlements, &Settlement{
+		Type:   kind,
+		Amount: amount,
+	})
+
+	return r
+}
+
+func (r *ReceiptRequest) Validate() error {
+	switch r.Type {
+	case ReceiptTypePayment:
+		if len(r.PaymentId) == 0 {
+			return newValidationError("payment_id", "Payment id is required for payment receipt")
+		}
+	case ReceiptTypeRefund:
+		if len(r.RefundId) == 0 && len(r.PaymentId) == 0 {
+			return newValidationError("refund_id", "Refund id or payment id is required for refund receipt")
+		}
+	default:
+		return newValidationError("type", "Receipt type must be payment or refund")
+	}
+
+	if !r.Send {
+		return newValidationError("send", "Receipt must be sent to the customer")
+	}
+
+	if err := validateReceipt(r.Customer, r.Items, r.TaxSystemCode, ""); err != nil {
+		return err
+	}
+
+	if err := validateReceiptDetails(r.ReceiptIndustryDetails, r.ReceiptOperationalDetails, ""); err != nil {
+		return err
+	}
+
+	if len(r.Settlements) == 0 {
+		return newValidationError("settlements", "Settlements are required")
+	}
+
+	total := decimal.Zero
+
+	for _, s := range r.Settlements {
+		if !s.Type.Valid() {
+			return newValidationError("settlements.type", "Unknown settlement type "+string(s.Type))
+		}
+
+		if s.Amount == nil || !s.Amount.Value.IsPositive() {
+			return newValidationError("settlements.amount.value", "Settlement amount must be greater than zero")
+		}
+
+		total = total.Add(s.Amount.Value)
+	}
+
+	if !total.Equal(itemsTotal(r.Items)) {
+		return newValidationError("settlements", "Settlements total "+total.StringFixed(2)+" doesn't match items total "+itemsTotal(r.Items).StringFixed(2))
+	}
+
+	return nil
+}
+
+func itemsTotal(items []*Item) decimal.Decimal {
+	total := decimal.Zero
+
+	for _, it := range items {
+		total = total.Add(it.Total())
+	}
+
+	return total
+}
+
+func validateReceipt(customer *Customer, items []*Item, taxSystem TaxSystemCode, prefix string) error {
+	if customer == nil || (len(customer.Email) == 0 && len(customer.Phone) == 0) {
+		return newValidationError(prefix+"customer", "Customer email or phone is required")
+	}
+
+	if len(customer.Email) > 0 && !emailPattern.MatchString(customer.Email) {
+		return newValidationError(prefix+"customer.email", "Customer email is invalid")
+	}
+
+	if len(customer.Phone) > 0 && !phonePattern.MatchString(customer.Phone) {
+		return newValidationError(prefix+"customer.phone", "Phone must be in ITU-T E.164 format without plus sign")
+	}
+
+	if taxSystem != 0 && !taxSystem.Valid() {
+		return newValidationError(prefix+"tax_system_code", "Tax system code must be from 1 to 6")
+	}
+
+	if len(items) == 0 {
+		return newValidationError(prefix+"items", "Receipt must contain at least one item")
+	}
+
+	for _, it := range items {
+		if len(it.Description) == 0 || len([]rune(it.Description)) > 128 {
+			return newValidationError(prefix+"items.description", "Item description must contain 1 to 128 characters")
+		}
+
+		if !it.Quantity.IsPositive() {
+			return newValidationError(prefix+"items.quantity", "Item quantity must be greater than zero")
+		}
+
+		if it.Amount == nil || it.Amount.Value.IsNegative() || !it.Amount.Value.Round(2).Equal(it.Amount.Value) {
+			return newValidationError(prefix+"items.amount.value", "Item price must be non-negative with at most two decimal places")
+		}
+
+		if !it.VATCode.Valid() {
+			return newValidationError(prefix+"items.vat_code", "Unknown VAT code")
+		}
+
+		if len(it.PaymentSubject) > 0 && !it.PaymentSubject.Valid() {
+			return newValidationError(prefix+"items.payment_subject", "Unknown payment subject "+string(it.PaymentSubject))
+		}
+
+		if len(it.PaymentMode) > 0 && !it.PaymentMode.Valid() {
+			return newValidationError(prefix+"items.payment_mode", "Unknown payment mode "+string(it.PaymentMode))
+		}
+
+		if len(it.AgentType) > 0 && !it.AgentType.Valid() {
+			return newValidationError(prefix+"items.agent_type", "Unknown agent type "+string(it.AgentType))
+		}
+
+		if err := it.validateFFD(prefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package yandex
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestReceipt() *Receipt {
+	return NewReceipt("user@example.com", "").
+		AddItem("Coffee", qty("2"), rub("150.50"), VATCode20).
+		AddItem("Beans", qty("0.5"), rub("100"), VATCodeNoVAT)
+}
+
+func TestReceiptTotal(t *testing.T) {
+	if total := newTestReceipt().Total(); !total.Equal(qty("351")) {
+		t.Errorf("Total() = %s, want 351", total)
+	}
+}
+
+func TestReceiptValidateFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount *Amount
+		modify func(r *Receipt)
+		param  string
+	}{
+		{"valid", rub("351"), nil, ""},
+		{"no amount", nil, nil, ""},
+		{"legacy email", rub("351"), func(r *Receipt) { r.Customer, r.Email = nil, "user@example.com" }, ""},
+		{"no customer", rub("351"), func(r *Receipt) { r.Customer = nil }, "receipt.customer"},
+		{"bad email", rub("351"), func(r *Receipt) { r.Customer.Email = "user" }, "receipt.customer.email"},
+		{"bad phone", rub("351"), func(r *Receipt) { r.Customer.Phone = "+79001234567" }, "receipt.customer.phone"},
+		{"tax system", rub("351"), func(r *Receipt) { r.WithTaxSystem(7) }, "receipt.tax_system_code"},
+		{"no items", rub("351"), func(r *Receipt) { r.Items = nil }, "receipt.items"},
+		{"no description", rub("351"), func(r *Receipt) { r.Items[0].Description = "" }, "receipt.items.description"},
+		{"zero quantity", rub("351"), func(r *Receipt) { r.Items[0].Quantity = decimal.Zero }, "receipt.items.quantity"},
+		{"fractional kopecks", rub("351"), func(r *Receipt) { r.Items[0].Amount = rub("150.505") }, "receipt.items.amount.value"},
+		{"vat code", rub("351"), func(r *Receipt) { r.Items[0].VATCode = 0 }, "receipt.items.vat_code"},
+		{"payment subject", rub("351"), func(r *Receipt) { r.Items[0].PaymentSubject = "goods" }, "receipt.items.payment_subject"},
+		{"payment mode", rub("351"), func(r *Receipt) { r.Items[0].PaymentMode = "later" }, "receipt.items.payment_mode"},
+		{"agent type", rub("351"), func(r *Receipt) { r.Items[0].AgentType = "broker" }, "receipt.items.agent_type"},
+		{"currency", &Amount{Value: qty("351"), Currency: "USD"}, nil, "receipt.items.amount.currency"},
+		{"total mismatch", rub("350"), nil, "receipt.items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReceipt()
+
+			if tt.modify != nil {
+				tt.modify(r)
+			}
+
+			err := r.ValidateFor(tt.amount)
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("ValidateFor() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("ValidateFor() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestNewReceiptRequest(t *testing.T) {
+	payment := NewReceiptRequest(ReceiptTypePayment, "p1", newTestReceipt())
+
+	if payment.PaymentId != "p1" || len(payment.RefundId) > 0 || !payment.Send || len(payment.Items) != 2 {
+		t.Errorf("NewReceiptRequest(payment) = %+v", payment)
+	}
+
+	refund := NewReceiptRequest(ReceiptTypeRefund, "r1", newTestReceipt())
+
+	if refund.RefundId != "r1" || len(refund.PaymentId) > 0 {
+		t.Errorf("NewReceiptRequest(refund) = %+v", refund)
+	}
+}
+
+func TestReceiptRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *ReceiptRequest)
+		param  string
+	}{
+		{"valid", nil, ""},
+		{"split settlements", func(r *ReceiptRequest) {
+			r.Settlements = nil
+			r.AddSettlement(SettlementTypePrepayment, rub("100")).AddSettlement(SettlementTypeCashless, rub("251"))
+		}, ""},
+		{"refund by payment", func(r *ReceiptRequest) { r.Type = ReceiptTypeRefund }, ""},
+		{"no payment id", func(r *ReceiptRequest) { r.PaymentId = "" }, "payment_id"},
+		{"no refund id", func(r *ReceiptRequest) { r.Type, r.PaymentId = ReceiptTypeRefund, "" }, "refund_id"},
+		{"type", func(r *ReceiptRequest) { r.Type = "correction" }, "type"},
+		{"not sent", func(r *ReceiptRequest) { r.Send = false }, "send"},
+		{"no customer", func(r *ReceiptRequest) { r.Customer = nil }, "customer"},
+		{"no items", func(r *ReceiptRequest) { r.Items = nil }, "items"},
+		{"no settlements", func(r *ReceiptRequest) { r.Settlements = nil }, "settlements"},
+		{"settlement type", func(r *ReceiptRequest) { r.Settlements[0].Type = "cash" }, "settlements.type"},
+		{"settlement amount", func(r *ReceiptRequest) { r.Settlements[0].Amount = nil }, "settlements.amount.value"},
+		{"settlements total", func(r *ReceiptRequest) { r.Settlements[0].Amount = rub("350") }, "settlements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReceiptRequest(ReceiptTypePayment, "p1", newTestReceipt()).
+				AddSettlement(SettlementTypeCashless, rub("351"))
+
+			if tt.modify != nil {
+				tt.modify(r)
+			}
+
+			err := r.Validate()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("Validate() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ go 1.14
 require (
 	github.com/gofrs/uuid v3.3.0+incompatible
 	github.com/google/go-querystring v1.0.0
-	github.com/valyala/fasthttp v1.15.1
 	github.com/shopspring/decimal v1.2.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	github.com/valyala/fasthttp v1.15.1
 )
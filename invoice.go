@@ -0,0 +1,280 @@
+package yandex
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	InvoiceStatusPending   string = "pending"
+	InvoiceStatusSucceeded string = "succeeded"
+	InvoiceStatusCanceled  string = "canceled"
+)
+
+const (
+	DeliveryMethodSelf string = "self"
+)
+
+type InvoiceCartItem struct {
+	Description   string          `json:"description"`
+	Price         *Amount         `json:"price"`
+	DiscountPrice *Amount         `json:"discount_price,omitempty"`
+	Quantity      decimal.Decimal `json:"quantity"`
+}
+
+type InvoicePaymentData struct {
+	Amount            *Amount                `json:"amount"`
+	Capture           bool                   `json:"capture,omitempty"`
+	Receipt           *Receipt               `json:"receipt,omitempty"`
+	SavePaymentMethod bool                   `json:"save_payment_method,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type DeliveryMethod struct {
+	Type string `json:"type"`
+	Url  string `json:"url,omitempty"`
+}
+
+type InvoicePaymentDetails struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type InvoiceDetails struct {
+	Id string `json:"id"`
+}
+
+type Invoice struct {
+	Id                  string                 `json:"id"`
+	Status              string                 `json:"status"`
+	Cart                []*InvoiceCartItem     `json:"cart"`
+	DeliveryMethod      *DeliveryMethod        `json:"delivery_method,omitempty"`
+	PaymentDetails      *InvoicePaymentDetails `json:"payment_details,omitempty"`
+	CreatedAt           string                 `json:"created_at"`
+	ExpiresAt           string                 `json:"expires_at,omitempty"`
+	Description         string                 `json:"description,omitempty"`
+	CancellationDetails *CancellationDetails   `json:"cancellation_details,omitempty"`
+	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type InvoiceRequest struct {
+	PaymentData        *InvoicePaymentData    `json:"payment_data"`
+	Cart               []*InvoiceCartItem     `json:"cart"`
+	DeliveryMethodData *DeliveryMethod        `json:"delivery_method_data,omitempty"`
+	Locale             string                 `json:"locale,omitempty"`
+	ExpiresAt          string                 `json:"expires_at"`
+	Description        string                 `json:"description,omitempty"`
+	Metadata           map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (y *Yandex) CreateInvoice(idempKey string, req *InvoiceRequest) (*Invoice, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/invoices",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.ShopId,
+		SecretKey:      y.SecretKey,
+		IdempotenceKey: idempKey,
+		Body:           req,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Invoice{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetInvoiceInfo(id string) (*Invoice, error) {
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/invoices/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Invoice{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) CancelInvoice(idempKey, id string) (*Invoice, error) {
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/invoices/" + id + "/cancel",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.ShopId,
+		SecretKey:      y.SecretKey,
+		IdempotenceKey: idempKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Invoice{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *InvoiceRequest) AddItem(description string, price *Amount, quantity decimal.Decimal) *InvoiceRequest {
+	r.Cart = append(r.Cart, &InvoiceCartItem{
+		Description: description,
+		Price:       price,
+		Quantity:    quantity,
+	})
+
+	return r
+}
+
+func (r *InvoiceRequest) WithExpiry(t time.Time) *InvoiceRequest {
+	r.ExpiresAt = t.UTC().Format(time.RFC3339)
+
+	return r
+}
+
+func (r *InvoiceRequest) WithSelfDelivery() *InvoiceRequest {
+	r.DeliveryMethodData = &DeliveryMethod{
+		Type: DeliveryMethodSelf,
+	}
+
+	return r
+}
+
+// Total is the sum the customer pays for the cart, discount prices take
+// precedence over regular ones.
+func (r *InvoiceRequest) Total() decimal.Decimal {
+	total := decimal.Zero
+
+	for _, it := range r.Cart {
+		price := it.Price
+
+		if it.DiscountPrice != nil {
+			price = it.DiscountPrice
+		}
+
+		if price != nil {
+			total = total.Add(price.Value.Mul(it.Quantity))
+		}
+	}
+
+	return total.Round(2)
+}
+
+func (r *InvoiceRequest) Validate() error {
+	if r.PaymentData == nil || r.PaymentData.Amount == nil || !r.PaymentData.Amount.Value.IsPositive() {
+		return newValidationError("payment_data.amount.value", "Amount must be greater than zero")
+	}
+
+	if len(r.Cart) == 0 {
+		return newValidationError("cart", "Cart must contain at least one item")
+	}
+
+	for _, it := range r.Cart {
+		if len(it.Description) == 0 || len([]rune(it.Description)) > 128 {
+			return newValidationError("cart.description", "Item description must contain 1 to 128 characters")
+		}
+
+		if it.Price == nil || !it.Price.Value.IsPositive() {
+			return newValidationError("cart.price", "Item price must be greater than zero")
+		}
+
+		if it.Price.Currency != r.PaymentData.Amount.Currency {
+			return newValidationError("cart.price.currency", "Item price currency must match payment amount currency")
+		}
+
+		if it.DiscountPrice != nil && (!it.DiscountPrice.Value.IsPositive() || it.DiscountPrice.Value.GreaterThan(it.Price.Value)) {
+			return newValidationError("cart.discount_price", "Item discount price must be greater than zero and not exceed price")
+		}
+
+		if !it.Quantity.IsPositive() {
+			return newValidationError("cart.quantity", "Item quantity must be greater than zero")
+		}
+	}
+
+	if !r.Total().Equal(r.PaymentData.Amount.Value) {
+		return newValidationError("payment_data.amount.value", "Amount must be equal to the cart total")
+	}
+
+	expires, err := time.Parse(time.RFC3339, r.ExpiresAt)
+
+	if err != nil {
+		return newValidationError("expires_at", "Expiry must be in ISO 8601 format")
+	}
+
+	if !expires.After(time.Now()) {
+		return newValidationError("expires_at", "Expiry must be in the future")
+	}
+
+	if r.DeliveryMethodData != nil && r.DeliveryMethodData.Type != DeliveryMethodSelf {
+		return newValidationError("delivery_method_data.type", "Only self delivery method is supported")
+	}
+
+	return nil
+}
+
+func (i *Invoice) PaymentId() string {
+	if i.PaymentDetails == nil {
+		return ""
+	}
+
+	return i.PaymentDetails.Id
+}
+
+func (p *Payment) InvoiceId() string {
+	if p.InvoiceDetails == nil {
+		return ""
+	}
+
+	return p.InvoiceDetails.Id
+}
+
+func (n *Notification) Invoice() (*Invoice, error) {
+	res := &Invoice{}
+
+	if err := json.Unmarshal(n.Object, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
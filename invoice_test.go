@@ -0,0 +1,105 @@
+package yandex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func qty(v string) decimal.Decimal {
+	return decimal.RequireFromString(v)
+}
+
+func newInvoice(amount string) *InvoiceRequest {
+	return (&InvoiceRequest{
+		PaymentData: &InvoicePaymentData{Amount: rub(amount)},
+	}).WithExpiry(time.Now().Add(time.Hour))
+}
+
+func TestInvoiceTotal(t *testing.T) {
+	r := newInvoice("0").
+		AddItem("Coffee", rub("150.50"), qty("2")).
+		AddItem("Beans", rub("1200"), qty("0.25"))
+
+	r.Cart[1].DiscountPrice = rub("1000")
+
+	if total := r.Total(); !total.Equal(qty("551")) {
+		t.Errorf("Total() = %s, want 551", total)
+	}
+}
+
+func TestInvoiceRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		modify func(r *InvoiceRequest)
+		param  string
+	}{
+		{"valid", "301", nil, ""},
+		{"self delivery", "301", func(r *InvoiceRequest) { r.WithSelfDelivery() }, ""},
+		{"zero amount", "0", nil, "payment_data.amount.value"},
+		{"empty cart", "301", func(r *InvoiceRequest) { r.Cart = nil }, "cart"},
+		{"no description", "301", func(r *InvoiceRequest) { r.Cart[0].Description = "" }, "cart.description"},
+		{"no price", "301", func(r *InvoiceRequest) { r.Cart[0].Price = nil }, "cart.price"},
+		{"price currency", "301", func(r *InvoiceRequest) { r.Cart[0].Price.Currency = "USD" }, "cart.price.currency"},
+		{"discount over price", "301", func(r *InvoiceRequest) { r.Cart[0].DiscountPrice = rub("200") }, "cart.discount_price"},
+		{"zero quantity", "301", func(r *InvoiceRequest) { r.Cart[0].Quantity = decimal.Zero }, "cart.quantity"},
+		{"total mismatch", "300", nil, "payment_data.amount.value"},
+		{"bad expiry", "301", func(r *InvoiceRequest) { r.ExpiresAt = "tomorrow" }, "expires_at"},
+		{"past expiry", "301", func(r *InvoiceRequest) { r.WithExpiry(time.Now().Add(-time.Minute)) }, "expires_at"},
+		{"delivery", "301", func(r *InvoiceRequest) { r.DeliveryMethodData = &DeliveryMethod{Type: "courier"} }, "delivery_method_data.type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newInvoice(tt.amount).AddItem("Coffee", rub("150.50"), qty("2"))
+
+			if tt.modify != nil {
+				tt.modify(r)
+			}
+
+			err := r.Validate()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("Validate() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestInvoiceLinks(t *testing.T) {
+	n := &Notification{
+		Event:  EventInvoiceSucceeded,
+		Object: json.RawMessage(`{"id":"in1","status":"succeeded","cart":[{"description":"Coffee","price":{"value":"150.50","currency":"RUB"},"quantity":"2.5"}],"payment_details":{"id":"p1","status":"succeeded"}}`),
+	}
+
+	inv, err := n.Invoice()
+
+	if err != nil {
+		t.Fatalf("Invoice() = %v", err)
+	}
+
+	if inv.PaymentId() != "p1" || !inv.Cart[0].Quantity.Equal(qty("2.5")) {
+		t.Errorf("Invoice() = %+v", inv)
+	}
+
+	p := &Payment{}
+
+	if err := json.Unmarshal([]byte(`{"id":"p1","invoice_details":{"id":"in1"}}`), p); err != nil || p.InvoiceId() != "in1" {
+		t.Errorf("InvoiceId() = %q, %v", p.InvoiceId(), err)
+	}
+
+	if (&Invoice{}).PaymentId() != "" || (&Payment{}).InvoiceId() != "" {
+		t.Error("empty links aren't empty")
+	}
+}
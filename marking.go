@@ -0,0 +1,262 @@
+package yandex
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const groupSeparator = '\x1d'
+
+var symbologyPrefixes = []string{"]d2", "]C1", "]Q3"}
+
+// Fixed length application identifiers that may appear in Chestny ZNAK
+// codes, the others are terminated by the group separator.
+var fixedIdentifiers = map[string]int{
+	"01":   14,
+	"11":   6,
+	"13":   6,
+	"15":   6,
+	"17":   6,
+	"8005": 6,
+}
+
+var variableIdentifiers = map[string]int{
+	"10":  20,
+	"21":  20,
+	"240": 30,
+	"91":  4,
+	"92":  88,
+	"93":  4,
+}
+
+type MarkingCode struct {
+	GTIN        string
+	Serial      string
+	KeyId       string
+	Signature   string
+	CryptoTail  string
+	Identifiers map[string]string
+
+	order []string
+}
+
+// ParseMarkingCode parses a GS1 DataMatrix code as returned by a scanner.
+// The group separator can be passed as is or as the <GS> or \u001d
+// placeholders some scanners and keyboard wedges produce.
+func ParseMarkingCode(code string) (*MarkingCode, error) {
+	s := strings.TrimSpace(code)
+	s = strings.Replace(s, "<GS>", string(groupSeparator), -1)
+	s = strings.Replace(s, `\u001d`, string(groupSeparator), -1)
+	s = strings.Replace(s, `\x1d`, string(groupSeparator), -1)
+
+	for _, p := range symbologyPrefixes {
+		s = strings.TrimPrefix(s, p)
+	}
+
+	s = strings.TrimLeft(s, string(groupSeparator))
+
+	if len(s) == 0 {
+		return nil, markingError(0, "code is empty")
+	}
+
+	res := &MarkingCode{
+		Identifiers: map[string]string{},
+	}
+
+	for pos := 0; pos < len(s); {
+		if s[pos] == groupSeparator {
+			pos++
+			continue
+		}
+
+		ai, size, fixed := identifierAt(s[pos:])
+
+		if len(ai) == 0 {
+			return nil, markingError(pos, fmt.Sprintf("unknown application identifier at %q", head(s[pos:])))
+		}
+
+		start := pos + len(ai)
+		end := start + size
+
+		if !fixed {
+			end = strings.IndexByte(s[start:], groupSeparator)
+
+			if end < 0 {
+				end = len(s)
+			} else {
+				end += start
+			}
+
+			if end-start > size {
+				return nil, markingError(start, fmt.Sprintf("value of (%s) is longer than %d characters, the group separator is probably missing", ai, size))
+			}
+		} else if end > len(s) {
+			return nil, markingError(start, fmt.Sprintf("value of (%s) must contain %d characters", ai, size))
+		}
+
+		value := s[start:end]
+
+		if len(value) == 0 {
+			return nil, markingError(start, fmt.Sprintf("value of (%s) is empty", ai))
+		}
+
+		for i := 0; i < len(value); i++ {
+			if value[i] < 0x21 || value[i] > 0x7e {
+				return nil, markingError(start+i, fmt.Sprintf("value of (%s) contains invalid character %q", ai, value[i]))
+			}
+		}
+
+		if _, ok := res.Identifiers[ai]; ok {
+			return nil, markingError(pos, fmt.Sprintf("application identifier (%s) is repeated", ai))
+		}
+
+		res.Identifiers[ai] = value
+		res.order = append(res.order, ai)
+		pos = end
+	}
+
+	res.GTIN = res.Identifiers["01"]
+	res.Serial = res.Identifiers["21"]
+	res.KeyId = res.Identifiers["91"]
+	res.Signature = res.Identifiers["92"]
+	res.CryptoTail = res.Identifiers["93"]
+
+	if len(res.GTIN) == 0 {
+		return nil, markingError(0, "GTIN (01) is missing")
+	}
+
+	if !validGTIN(res.GTIN) {
+		return nil, markingError(2, "GTIN (01) "+res.GTIN+" has invalid check digit")
+	}
+
+	if len(res.Serial) == 0 {
+		return nil, markingError(0, "serial number (21) is missing")
+	}
+
+	if len(res.KeyId) > 0 && len(res.Signature) == 0 {
+		return nil, markingError(0, "verification key (91) is set without crypto signature (92)")
+	}
+
+	if len(res.Signature) > 0 && len(res.Signature) != 44 && len(res.Signature) != 88 {
+		return nil, markingError(0, "crypto signature (92) must contain 44 or 88 characters")
+	}
+
+	return res, nil
+}
+
+// String returns the code in the canonical GS1 form, with a group
+// separator after every variable length value except the last one.
+func (c *MarkingCode) String() string {
+	b := strings.Builder{}
+	sep := false
+
+	for _, ai := range c.order {
+		if sep {
+			b.WriteByte(groupSeparator)
+		}
+
+		b.WriteString(ai)
+		b.WriteString(c.Identifiers[ai])
+
+		_, fixed := fixedIdentifiers[ai]
+		sep = !fixed
+	}
+
+	return b.String()
+}
+
+// ProductCode returns the tag 1162 value: DataMatrix type code, GTIN as a
+// 6 byte number and the serial number, formatted as space separated hex.
+func (c *MarkingCode) ProductCode() string {
+	gtin, _ := new(big.Int).SetString(c.GTIN, 10)
+	bytes := []byte{0x44, 0x4d}
+	g := gtin.Bytes()
+
+	for i := len(g); i < 6; i++ {
+		bytes = append(bytes, 0)
+	}
+
+	bytes = append(bytes, g...)
+	bytes = append(bytes, c.Serial...)
+
+	parts := make([]string, len(bytes))
+
+	for i, b := range bytes {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// MarkCodeInfo returns the FFD 1.2 structure, the API expects the GS1
+// DataMatrix code encoded in base64.
+func (c *MarkingCode) MarkCodeInfo() *MarkCodeInfo {
+	return &MarkCodeInfo{
+		GS1M: base64.StdEncoding.EncodeToString([]byte(c.String())),
+	}
+}
+
+func (it *Item) WithMarkingCode(c *MarkingCode) *Item {
+	it.ProductCode = c.ProductCode()
+	it.MarkCodeInfo = c.MarkCodeInfo()
+	it.MarkMode = MarkModeDefault
+
+	return it
+}
+
+func identifierAt(s string) (string, int, bool) {
+	for n := 2; n <= 4 && n <= len(s); n++ {
+		ai := s[:n]
+
+		if size, ok := fixedIdentifiers[ai]; ok {
+			return ai, size, true
+		}
+
+		if size, ok := variableIdentifiers[ai]; ok {
+			return ai, size, false
+		}
+	}
+
+	return "", 0, false
+}
+
+func validGTIN(gtin string) bool {
+	if len(gtin) != 14 {
+		return false
+	}
+
+	sum := 0
+
+	for i := 0; i < 13; i++ {
+		d, err := strconv.Atoi(gtin[i : i+1])
+
+		if err != nil {
+			return false
+		}
+
+		if i%2 == 0 {
+			sum += d * 3
+		} else {
+			sum += d
+		}
+	}
+
+	check, err := strconv.Atoi(gtin[13:])
+
+	return err == nil && (10-sum%10)%10 == check
+}
+
+func head(s string) string {
+	if len(s) > 8 {
+		return s[:8] + "..."
+	}
+
+	return s
+}
+
+func markingError(pos int, reason string) *Error {
+	return newValidationError("mark_code_info", fmt.Sprintf("Invalid marking code at position %d: %s", pos, reason))
+}
@@ -0,0 +1,163 @@
+package yandex
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const (
+	testGTIN      = "04604060005904"
+	testSignature = "dGVzdHRlc3R0ZXN0dGVzdHRlc3R0ZXN0dGVzdHRlc3Q="
+)
+
+func TestValidGTIN(t *testing.T) {
+	tests := []struct {
+		gtin string
+		want bool
+	}{
+		{testGTIN, true},
+		{"04006381333931", true},
+		{"00000000000017", true},
+		{"04604060005905", false},
+		{"0460406000590", false},
+		{"0460406000590A", false},
+	}
+
+	for _, tt := range tests {
+		if got := validGTIN(tt.gtin); got != tt.want {
+			t.Errorf("validGTIN(%q) = %v, want %v", tt.gtin, got, tt.want)
+		}
+	}
+}
+
+func TestParseMarkingCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		serial     string
+		keyId      string
+		cryptoTail string
+		canonical  string
+	}{
+		{
+			name:      "group separators",
+			code:      "01" + testGTIN + "2160N4N57RTCBUZ\x1d91FFD0\x1d92" + testSignature,
+			serial:    "60N4N57RTCBUZ",
+			keyId:     "FFD0",
+			canonical: "01" + testGTIN + "2160N4N57RTCBUZ\x1d91FFD0\x1d92" + testSignature,
+		},
+		{
+			name:      "symbology prefix and placeholders",
+			code:      "]d201" + testGTIN + "2160N4N57RTCBUZ<GS>91FFD0\\u001d92" + testSignature,
+			serial:    "60N4N57RTCBUZ",
+			keyId:     "FFD0",
+			canonical: "01" + testGTIN + "2160N4N57RTCBUZ\x1d91FFD0\x1d92" + testSignature,
+		},
+		{
+			name:       "crypto tail",
+			code:       "01" + testGTIN + "215ooc\x1d93dGVz",
+			serial:     "5ooc",
+			cryptoTail: "dGVz",
+			canonical:  "01" + testGTIN + "215ooc\x1d93dGVz",
+		},
+		{
+			name:      "serial only",
+			code:      "01" + testGTIN + "21ABC",
+			serial:    "ABC",
+			canonical: "01" + testGTIN + "21ABC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseMarkingCode(tt.code)
+
+			if err != nil {
+				t.Fatalf("ParseMarkingCode() = %v", err)
+			}
+
+			if c.GTIN != testGTIN || c.Serial != tt.serial || c.KeyId != tt.keyId || c.CryptoTail != tt.cryptoTail {
+				t.Errorf("got %+v", c)
+			}
+
+			if got := c.String(); got != tt.canonical {
+				t.Errorf("String() = %q, want %q", got, tt.canonical)
+			}
+		})
+	}
+}
+
+func TestParseMarkingCodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"empty", "  ", "code is empty"},
+		{"check digit", "0104604060005905" + "21ABC", "invalid check digit"},
+		{"short GTIN", "010460406", "must contain 14 characters"},
+		{"missing serial", "01" + testGTIN, "serial number (21) is missing"},
+		{"missing GTIN", "21ABC", "GTIN (01) is missing"},
+		{"missing separator", "01" + testGTIN + "2160N4N57RTCBUZ91FFD092" + testSignature, "group separator is probably missing"},
+		{"unknown identifier", "01" + testGTIN + "21ABC\x1d55XYZ", "unknown application identifier"},
+		{"key without signature", "01" + testGTIN + "21ABC\x1d91FFD0", "without crypto signature"},
+		{"signature length", "01" + testGTIN + "21ABC\x1d91FFD0\x1d92short", "44 or 88 characters"},
+		{"repeated identifier", "01" + testGTIN + "21ABC\x1d21DEF", "is repeated"},
+		{"invalid character", "01" + testGTIN + "21AB C", "invalid character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMarkingCode(tt.code)
+
+			if err == nil {
+				t.Fatal("ParseMarkingCode() succeeded")
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q doesn't mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkingCodeConversions(t *testing.T) {
+	tests := []struct {
+		code        string
+		productCode string
+	}{
+		{
+			code:        "01" + testGTIN + "2160N4N57RTCBUZ\x1d91FFD0\x1d92" + testSignature,
+			productCode: "44 4D 04 2F F7 5C 76 10 36 30 4E 34 4E 35 37 52 54 43 42 55 5A",
+		},
+		{
+			code:        "0100000000000017" + "21A",
+			productCode: "44 4D 00 00 00 00 00 11 41",
+		},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseMarkingCode(tt.code)
+
+		if err != nil {
+			t.Fatalf("ParseMarkingCode() = %v", err)
+		}
+
+		if got := c.ProductCode(); got != tt.productCode {
+			t.Errorf("ProductCode() = %q, want %q", got, tt.productCode)
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(c.MarkCodeInfo().GS1M)
+
+		if err != nil || string(raw) != c.String() {
+			t.Errorf("MarkCodeInfo().GS1M decodes to %q, %v", raw, err)
+		}
+
+		it := (&Item{}).WithMarkingCode(c)
+
+		if it.ProductCode != tt.productCode || it.MarkMode != MarkModeDefault || it.MarkCodeInfo == nil {
+			t.Errorf("WithMarkingCode() = %+v", it)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package yandex
+
+import (
+	"encoding/json"
+	"log"
+	"regexp"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PaymentMethodBankCard              string = "bank_card"
+	PaymentMethodSberbank              string = "sberbank"
+	PaymentMethodTinkoffBank           string = "tinkoff_bank"
+	PaymentMethodYooMoney              string = "yoo_money"
+	PaymentMethodSBP                   string = "sbp"
+	PaymentMethodMobileBalance         string = "mobile_balance"
+	PaymentMethodB2BSberbank           string = "b2b_sberbank"
+	PaymentMethodCash                  string = "cash"
+	PaymentMethodInstallments          string = "installments"
+	PaymentMethodSberLoan              string = "sber_loan"
+	PaymentMethodSberBNPL              string = "sber_bnpl"
+	PaymentMethodElectronicCertificate string = "electronic_certificate"
+	PaymentMethodApplePay              string = "apple_pay"
+	PaymentMethodGooglePay             string = "google_pay"
+
+	// Deprecated: Alfa-Click is no longer supported by the API.
+	PaymentMethodAlfaBank string = "alfabank"
+	// Deprecated: use PaymentMethodYooMoney.
+	PaymentMethodYandexMoney string = "yandex_money"
+	// Deprecated: QIWI Wallet is no longer supported by the API.
+	PaymentMethodQiwi string = "qiwi"
+	// Deprecated: WeChat Pay is no longer supported by the API.
+	PaymentMethodWeChat string = "wechat"
+	// Deprecated: Webmoney is no longer supported by the API.
+	PaymentMethodWebmoney string = "webmoney"
+)
+
+type ElectronicCertificate struct {
+	Amount   *Amount `json:"amount"`
+	BasketId string  `json:"basket_id"`
+}
+
+type CertificateArticle struct {
+	ArticleNumber uint32                 `json:"article_number"`
+	TruCode       string                 `json:"tru_code"`
+	ArticleCode   string                 `json:"article_code,omitempty"`
+	ArticleName   string                 `json:"article_name,omitempty"`
+	Quantity      uint32                 `json:"quantity"`
+	Price         *Amount                `json:"price"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type PaymentMethodValidator func(m *PaymentMethod) error
+
+type paymentMethodSpec struct {
+	Validate   PaymentMethodValidator
+	Deprecated bool
+}
+
+var (
+	phonePattern   = regexp.MustCompile(`^[0-9]{11,15}$`)
+	cardPattern    = regexp.MustCompile(`^[0-9]{12,19}$`)
+	yearPattern    = regexp.MustCompile(`^[0-9]{4}$`)
+	monthPattern   = regexp.MustCompile(`^(0[1-9]|1[0-2])$`)
+	cscPattern     = regexp.MustCompile(`^[0-9]{3,4}$`)
+	paymentMethods = map[string]*paymentMethodSpec{
+		PaymentMethodBankCard:              {Validate: validateBankCard},
+		PaymentMethodSberbank:              {Validate: validateOptionalPhone},
+		PaymentMethodTinkoffBank:           {},
+		PaymentMethodYooMoney:              {},
+		PaymentMethodSBP:                   {},
+		PaymentMethodMobileBalance:         {Validate: validateRequiredPhone},
+		PaymentMethodB2BSberbank:           {Validate: validateB2BSberbank},
+		PaymentMethodCash:                  {Validate: validateOptionalPhone},
+		PaymentMethodInstallments:          {},
+		PaymentMethodSberLoan:              {},
+		PaymentMethodSberBNPL:              {},
+		PaymentMethodElectronicCertificate: {Validate: validateElectronicCertificate},
+		PaymentMethodApplePay:              {Validate: validateApplePay},
+		PaymentMethodGooglePay:             {Validate: validateGooglePay},
+		PaymentMethodAlfaBank:              {Deprecated: true},
+		PaymentMethodYandexMoney:           {Deprecated: true},
+		PaymentMethodQiwi:                  {Validate: validateOptionalPhone, Deprecated: true},
+		PaymentMethodWeChat:                {Deprecated: true},
+		PaymentMethodWebmoney:              {Deprecated: true},
+	}
+	paymentMethodsMu sync.RWMutex
+)
+
+// PaymentMethodSpec describes a payment method type unknown to this
+// package. Details decodes it in responses and Support lists the payment
+// flows it can be used with, both are optional.
+type PaymentMethodSpec struct {
+	Validate   PaymentMethodValidator
+	Details    func() PaymentMethodDetails
+	Support    *PaymentMethodSupport
+	Deprecated bool
+}
+
+// RegisterPaymentMethod adds a method type unknown to this package, its
+// specific fields can be passed in PaymentMethod.Extra.
+func RegisterPaymentMethod(kind string, spec *PaymentMethodSpec) {
+	paymentMethodsMu.Lock()
+	defer paymentMethodsMu.Unlock()
+
+	paymentMethods[kind] = &paymentMethodSpec{
+		Validate:   spec.Validate,
+		Deprecated: spec.Deprecated,
+	}
+
+	if spec.Details != nil {
+		paymentMethodDetails[kind] = spec.Details
+	}
+
+	if spec.Support != nil {
+		paymentMethodSupport[kind] = spec.Support
+	}
+}
+
+func IsDeprecatedPaymentMethod(kind string) bool {
+	paymentMethodsMu.RLock()
+	defer paymentMethodsMu.RUnlock()
+
+	spec, ok := paymentMethods[kind]
+
+	return ok && spec.Deprecated
+}
+
+func (m *PaymentMethod) Validate() error {
+	paymentMethodsMu.RLock()
+	spec, ok := paymentMethods[m.Type]
+	paymentMethodsMu.RUnlock()
+
+	if !ok {
+		return newValidationError("payment_method_data.type", "Unknown payment method type "+m.Type)
+	}
+
+	if spec.Validate == nil {
+		return nil
+	}
+
+	return spec.Validate(m)
+}
+
+func (m *PaymentMethod) MarshalJSON() ([]byte, error) {
+	type plain PaymentMethod
+
+	if m.PayerBankDetails != nil && m.SBPPayerBankDetails != nil {
+		return nil, newValidationError("payment_method.payer_bank_details", "Only one of B2B and SBP payer bank details can be specified")
+	}
+
+	bytes, err := json.Marshal((*plain)(m))
+
+	var payer interface{}
+
+	switch {
+	case m.PayerBankDetails != nil:
+		payer = m.PayerBankDetails
+	case m.SBPPayerBankDetails != nil:
+		payer = m.SBPPayerBankDetails
+	}
+
+	if err != nil || (len(m.Extra) == 0 && payer == nil) {
+		return bytes, err
+	}
+
+	fields := map[string]interface{}{}
+
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	if payer != nil {
+		fields["payer_bank_details"] = payer
+	}
+
+	for k, v := range m.Extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = v
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+func validateBankCard(m *PaymentMethod) error {
+	if m.Card == nil {
+		return nil
+	}
+
+	if !cardPattern.MatchString(m.Card.Number) {
+		return newValidationError("payment_method_data.card.number", "Card number must contain 12 to 19 digits")
+	}
+
+	if !yearPattern.MatchString(m.Card.ExpiryYear) {
+		return newValidationError("payment_method_data.card.expiry_year", "Card expiry year must be in YYYY format")
+	}
+
+	if !monthPattern.MatchString(m.Card.ExpiryMonth) {
+		return newValidationError("payment_method_data.card.expiry_month", "Card expiry month must be in MM format")
+	}
+
+	if len(m.Card.CSC) > 0 && !cscPattern.MatchString(m.Card.CSC) {
+		return newValidationError("payment_method_data.card.csc", "Card CSC must contain 3 or 4 digits")
+	}
+
+	return nil
+}
+
+func validateOptionalPhone(m *PaymentMethod) error {
+	if len(m.Phone) > 0 && !phonePattern.MatchString(m.Phone) {
+		return newValidationError("payment_method_data.phone", "Phone must be in ITU-T E.164 format without plus sign")
+	}
+
+	return nil
+}
+
+func validateRequiredPhone(m *PaymentMethod) error {
+	if len(m.Phone) == 0 {
+		return newValidationError("payment_method_data.phone", "Phone is required for "+m.Type+" payment method")
+	}
+
+	return validateOptionalPhone(m)
+}
+
+func validateB2BSberbank(m *PaymentMethod) error {
+	if len(m.PaymentPurpose) == 0 || len([]rune(m.PaymentPurpose)) > 210 {
+		return newValidationError("payment_method_data.payment_purpose", "Payment purpose must contain 1 to 210 characters")
+	}
+
+	if m.VATData == nil {
+		return newValidationError("payment_method_data.vat_data", "VAT data is required for b2b_sberbank payment method")
+	}
+
+	switch m.VATData.Type {
+	case "untaxed":
+	case "calculated":
+		if len(m.VATData.Rate) == 0 {
+			return newValidationError("payment_method_data.vat_data.rate", "VAT rate is required for calculated VAT")
+		}
+
+		fallthrough
+	case "mixed":
+		if m.VATData.Amount == nil || !m.VATData.Amount.Value.IsPositive() {
+			return newValidationError("payment_method_data.vat_data.amount", "VAT amount is required for "+m.VATData.Type+" VAT")
+		}
+	default:
+		return newValidationError("payment_method_data.vat_data.type", "VAT data type must be calculated, untaxed or mixed")
+	}
+
+	return nil
+}
+
+func validateElectronicCertificate(m *PaymentMethod) error {
+	if err := validateBankCard(m); err != nil {
+		return err
+	}
+
+	if (m.ElectronicCertificate == nil) != (len(m.Articles) == 0) {
+		return newValidationError("payment_method_data.articles", "Electronic certificate and articles must be specified together")
+	}
+
+	if m.ElectronicCertificate == nil {
+		return nil
+	}
+
+	if m.ElectronicCertificate.Amount == nil || !m.ElectronicCertificate.Amount.Value.IsPositive() {
+		return newValidationError("payment_method_data.electronic_certificate.amount", "Electronic certificate amount must be greater than zero")
+	}
+
+	if len(m.ElectronicCertificate.BasketId) == 0 {
+		return newValidationError("payment_method_data.electronic_certificate.basket_id", "Electronic certificate basket id is required")
+	}
+
+	total := decimal.Zero
+
+	for _, a := range m.Articles {
+		if len(a.TruCode) == 0 {
+			return newValidationError("payment_method_data.articles.tru_code", "Article TRU code is required")
+		}
+
+		if a.Quantity == 0 || a.Price == nil {
+			return newValidationError("payment_method_data.articles", "Article quantity and price are required")
+		}
+
+		total = total.Add(a.Price.Value.Mul(decimal.NewFromInt(int64(a.Quantity))))
+	}
+
+	if m.ElectronicCertificate.Amount.Value.GreaterThan(total) {
+		return newValidationError("payment_method_data.electronic_certificate.amount", "Electronic certificate amount exceeds articles total")
+	}
+
+	return nil
+}
+
+func validateApplePay(m *PaymentMethod) error {
+	if len(m.PaymentData) == 0 {
+		return newValidationError("payment_method_data.payment_data", "Payment data is required for apple_pay payment method")
+	}
+
+	return nil
+}
+
+func validateGooglePay(m *PaymentMethod) error {
+	if len(m.PaymentMethodToken) == 0 {
+		return newValidationError("payment_method_data.payment_method_token", "Payment method token is required for google_pay payment method")
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package yandex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type failingDoer struct {
+	calls int
+}
+
+func (d *failingDoer) Do(req *fasthttp.Request, res *fasthttp.Response) error {
+	d.calls++
+
+	return errors.New("unexpected request")
+}
+
+func testCard(number string) *Card {
+	return &Card{
+		Number:      number,
+		ExpiryYear:  "2030",
+		ExpiryMonth: "12",
+	}
+}
+
+func TestPaymentMethodBuilders(t *testing.T) {
+	cert := &ElectronicCertificate{Amount: rub("100"), BasketId: "basket"}
+	articles := []*CertificateArticle{{ArticleNumber: 1, TruCode: "329921120.06001010200080001643", Quantity: 1, Price: rub("100")}}
+
+	tests := []struct {
+		name  string
+		build func(r *PaymentRequest) *PaymentRequest
+		param string
+	}{
+		{"bank card", func(r *PaymentRequest) *PaymentRequest { return r.WithBankCard(testCard("5555555555554444")) }, ""},
+		{"bank card number", func(r *PaymentRequest) *PaymentRequest { return r.WithBankCard(testCard("5555")) }, "payment_method_data.card.number"},
+		{"bank card csc", func(r *PaymentRequest) *PaymentRequest {
+			c := testCard("5555555555554444")
+			c.CSC = "12"
+			return r.WithBankCard(c)
+		}, "payment_method_data.card.csc"},
+		{"mobile balance", func(r *PaymentRequest) *PaymentRequest { return r.WithPhoneBalance("79991234567") }, ""},
+		{"mobile balance without phone", func(r *PaymentRequest) *PaymentRequest { return r.WithPhoneBalance("") }, "payment_method_data.phone"},
+		{"sberpay phone", func(r *PaymentRequest) *PaymentRequest { return r.WithSberbank("+7 999 123") }, "payment_method_data.phone"},
+		{"b2b", func(r *PaymentRequest) *PaymentRequest {
+			return r.WithSberbankB2B("Invoice 1", &VATData{Type: "calculated", Rate: "20", Amount: rub("20")})
+		}, ""},
+		{"b2b without purpose", func(r *PaymentRequest) *PaymentRequest { return r.WithSberbankB2B("", &VATData{Type: "untaxed"}) }, "payment_method_data.payment_purpose"},
+		{"b2b without vat", func(r *PaymentRequest) *PaymentRequest { return r.WithSberbankB2B("Invoice 1", nil) }, "payment_method_data.vat_data"},
+		{"b2b without rate", func(r *PaymentRequest) *PaymentRequest {
+			return r.WithSberbankB2B("Invoice 1", &VATData{Type: "calculated", Amount: rub("20")})
+		}, "payment_method_data.vat_data.rate"},
+		{"electronic certificate", func(r *PaymentRequest) *PaymentRequest {
+			return r.WithElectronicCertificate(testCard("2202474301322987"), cert, articles)
+		}, ""},
+		{"certificate without articles", func(r *PaymentRequest) *PaymentRequest {
+			return r.WithElectronicCertificate(testCard("2202474301322987"), cert, nil)
+		}, "payment_method_data.articles"},
+		{"certificate over articles", func(r *PaymentRequest) *PaymentRequest {
+			return r.WithElectronicCertificate(nil, &ElectronicCertificate{Amount: rub("150"), BasketId: "basket"}, articles)
+		}, "payment_method_data.electronic_certificate.amount"},
+		{"apple pay", func(r *PaymentRequest) *PaymentRequest { return r.WithApplePay("") }, "payment_method_data.payment_data"},
+		{"google pay", func(r *PaymentRequest) *PaymentRequest { return r.WithGooglePay("") }, "payment_method_data.payment_method_token"},
+		{"sbp", func(r *PaymentRequest) *PaymentRequest { return r.WithSBP() }, ""},
+		{"yoo money", func(r *PaymentRequest) *PaymentRequest { return r.WithYooMoney() }, ""},
+		{"sber loan", func(r *PaymentRequest) *PaymentRequest { return r.WithSberLoan() }, ""},
+		{"unknown type", func(r *PaymentRequest) *PaymentRequest { return r.WithPaymentMethod(&PaymentMethod{Type: "unknown"}) }, "payment_method_data.type"},
+		{"fixed by next builder", func(r *PaymentRequest) *PaymentRequest { return r.WithPhoneBalance("").WithSBP() }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.build(&PaymentRequest{Amount: rub("100")})
+			err := r.Err()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Err() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Fatalf("Err() = %v, want error for %s", err, tt.param)
+			}
+
+			d := &failingDoer{}
+			y := &Yandex{HttpClient: d}
+
+			if _, err := y.CreatePayment("key", r); err != r.Err() {
+				t.Errorf("CreatePayment() = %v, want %v", err, r.Err())
+			}
+
+			if d.calls > 0 {
+				t.Error("CreatePayment() sent a request with invalid payment method")
+			}
+		})
+	}
+}
+
+func TestRegisterPaymentMethod(t *testing.T) {
+	RegisterPaymentMethod("test_wallet", &PaymentMethodSpec{
+		Validate: func(m *PaymentMethod) error {
+			if _, ok := m.Extra["wallet_id"]; !ok {
+				return newValidationError("payment_method_data.wallet_id", "Wallet id is required")
+			}
+
+			return nil
+		},
+		Details: func() PaymentMethodDetails { return &WalletMethod{} },
+		Support: &PaymentMethodSupport{Confirmations: []string{ConfirmationRedirect}},
+	})
+
+	r := (&PaymentRequest{}).WithPaymentMethod(&PaymentMethod{Type: "test_wallet"})
+
+	if r.Err() == nil {
+		t.Error("Err() without wallet id is nil")
+	}
+
+	r.WithPaymentMethod(&PaymentMethod{Type: "test_wallet", Extra: map[string]interface{}{"wallet_id": "w1"}})
+
+	if err := r.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+
+	if s, ok := GetPaymentMethodSupport("test_wallet"); !ok || !s.SupportsConfirmation(ConfirmationRedirect) {
+		t.Errorf("GetPaymentMethodSupport() = %v, %t", s, ok)
+	}
+
+	m := &PaymentMethod{}
+
+	if err := json.Unmarshal([]byte(`{"type":"test_wallet","id":"pm1","saved":false,"login":"user"}`), m); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+
+	if d, err := m.Details(); err != nil || d.(*WalletMethod).Login != "user" {
+		t.Errorf("Details() = %v, %v", d, err)
+	}
+
+	if IsDeprecatedPaymentMethod("test_wallet") || !IsDeprecatedPaymentMethod(PaymentMethodQiwi) {
+		t.Error("IsDeprecatedPaymentMethod() is wrong")
+	}
+}
+
+func TestPaymentMethodMarshal(t *testing.T) {
+	m := &PaymentMethod{
+		Type:  PaymentMethodSBP,
+		Extra: map[string]interface{}{"type": "other", "bank": "sber"},
+		SBPPayerBankDetails: &SBPPayerBankDetails{
+			BankId: "100000000111",
+			BIC:    "044525225",
+		},
+	}
+
+	b, err := json.Marshal(m)
+
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+
+	fields := map[string]interface{}{}
+	json.Unmarshal(b, &fields)
+
+	if fields["type"] != PaymentMethodSBP || fields["bank"] != "sber" || fields["payer_bank_details"] == nil {
+		t.Errorf("Marshal() = %s", b)
+	}
+
+	m.PayerBankDetails = &PayerBankDetails{INN: "7707083893"}
+
+	if _, err := json.Marshal(m); err == nil {
+		t.Error("Marshal() with both payer bank details succeeded")
+	}
+}
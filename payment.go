@@ -3,8 +3,42 @@ package yandex
 import (
 	"encoding/json"
 	"log"
+)
+
+const (
+	PaymentStatusPending           string = "pending"
+	PaymentStatusWaitingForCapture string = "waiting_for_capture"
+	PaymentStatusSucceeded         string = "succeeded"
+	PaymentStatusCanceled          string = "canceled"
+)
+
+const (
+	CancellationPartyMerchant       string = "merchant"
+	CancellationPartyYooMoney       string = "yoo_money"
+	CancellationPartyPaymentNetwork string = "payment_network"
+)
 
-	"github.com/google/go-querystring/query"
+const (
+	CancellationReason3DSecureFailed             string = "3d_secure_failed"
+	CancellationReasonCallIssuer                 string = "call_issuer"
+	CancellationReasonCanceledByMerchant         string = "canceled_by_merchant"
+	CancellationReasonCardExpired                string = "card_expired"
+	CancellationReasonCountryForbidden           string = "country_forbidden"
+	CancellationReasonDealExpired                string = "deal_expired"
+	CancellationReasonExpiredOnCapture           string = "expired_on_capture"
+	CancellationReasonExpiredOnConfirmation      string = "expired_on_confirmation"
+	CancellationReasonFraudSuspected             string = "fraud_suspected"
+	CancellationReasonGeneralDecline             string = "general_decline"
+	CancellationReasonIdentificationRequired     string = "identification_required"
+	CancellationReasonInsufficientFunds          string = "insufficient_funds"
+	CancellationReasonInternalTimeout            string = "internal_timeout"
+	CancellationReasonInvalidCardNumber          string = "invalid_card_number"
+	CancellationReasonInvalidCSC                 string = "invalid_csc"
+	CancellationReasonIssuerUnavailable          string = "issuer_unavailable"
+	CancellationReasonPaymentMethodLimitExceeded string = "payment_method_limit_exceeded"
+	CancellationReasonPaymentMethodRestricted    string = "payment_method_restricted"
+	CancellationReasonPermissionRevoked          string = "permission_revoked"
+	CancellationReasonUnsupportedMobileOperator  string = "unsupported_mobile_operator"
 )
 
 type VATData struct {
@@ -40,19 +74,28 @@ type Card struct {
 }
 
 type PaymentMethod struct {
-	Type               string            `json:"type"`
-	Id                 string            `json:"id,omitempty"`
-	Saved              bool              `json:"saved,omitempty"`
-	Title              string            `json:"title,omitempty"`
-	Login              string            `json:"login,omitempty"`
-	Phone              string            `json:"phone,omitempty"`
-	Card               *Card             `json:"card,omitempty"`
-	PaymentPurpose     string            `json:"payment_purpose,omitempty"`
-	VATData            *VATData          `json:"vat_data,omitempty"`
-	PaymentData        string            `json:"payment_data,omitempty"`
-	PaymentMethodToken string            `json:"payment_method_token,omitempty"`
-	PayerBankDetails   *PayerBankDetails `json:"payer_bank_details,omitempty"`
-	AccountNumber      string            `json:"account_number,omitempty"`
+	Type                  string                 `json:"type"`
+	Id                    string                 `json:"id,omitempty"`
+	Saved                 bool                   `json:"saved,omitempty"`
+	Title                 string                 `json:"title,omitempty"`
+	Login                 string                 `json:"login,omitempty"`
+	Phone                 string                 `json:"phone,omitempty"`
+	Card                  *Card                  `json:"card,omitempty"`
+	PaymentPurpose        string                 `json:"payment_purpose,omitempty"`
+	VATData               *VATData               `json:"vat_data,omitempty"`
+	PaymentData           string                 `json:"payment_data,omitempty"`
+	PaymentMethodToken    string                 `json:"payment_method_token,omitempty"`
+	PayerBankDetails      *PayerBankDetails      `json:"-"`
+	SBPPayerBankDetails   *SBPPayerBankDetails   `json:"-"`
+	SbpOperationId        string                 `json:"sbp_operation_id,omitempty"`
+	AccountNumber         string                 `json:"account_number,omitempty"`
+	GoogleTransactionId   string                 `json:"google_transaction_id,omitempty"`
+	ElectronicCertificate *ElectronicCertificate `json:"electronic_certificate,omitempty"`
+	Articles              []*CertificateArticle  `json:"articles,omitempty"`
+	Extra                 map[string]interface{} `json:"-"`
+
+	raw     json.RawMessage
+	details PaymentMethodDetails
 }
 
 type Leg struct {
@@ -75,9 +118,12 @@ type Airline struct {
 }
 
 type Transfer struct {
-	AccountId string  `json:"account_id"`
-	Amount    *Amount `json:"amount"`
-	Status    string  `json:"status,omitempty"`
+	AccountId         string                 `json:"account_id"`
+	Amount            *Amount                `json:"amount"`
+	Status            string                 `json:"status,omitempty"`
+	PlatformFeeAmount *Amount                `json:"platform_fee_amount,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type Confirmation struct {
@@ -133,6 +179,8 @@ type Payment struct {
 	CancellationDetails  *CancellationDetails   `json:"cancellation_details,omitempty"`
 	AuthorizationDetails *AuthorizationDetails  `json:"authorization_details,omitempty"`
 	Transfers            []*Transfer            `json:"transfers,omitempty"`
+	Deal                 *PaymentDeal           `json:"deal,omitempty"`
+	InvoiceDetails       *InvoiceDetails        `json:"invoice_details,omitempty"`
 }
 
 type PaymentRequest struct {
@@ -150,23 +198,25 @@ type PaymentRequest struct {
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
 	Airline           *Airline               `json:"airline,omitempty"`
 	Transfers         []*Transfer            `json:"transfers,omitempty"`
+	Deal              *PaymentDeal           `json:"deal,omitempty"`
+
+	methodErr error
 }
 
 func (y *Yandex) CreatePayment(idempKey string, req *PaymentRequest) (*Payment, error) {
-	q, err := query.Values(req)
-
-	if err != nil {
-		log.Printf("Failed creating query: %v\n", err)
+	if err := req.Err(); err != nil {
 		return nil, err
 	}
 
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/payments",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		ShopId:         y.ShopId,
 		SecretKey:      y.SecretKey,
 		IdempotenceKey: idempKey,
-		Data:           q,
+		Body:           req,
 	}
 
 	bytes, err := r.SendRequest()
@@ -187,10 +237,12 @@ func (y *Yandex) CreatePayment(idempKey string, req *PaymentRequest) (*Payment,
 
 func (y *Yandex) GetPaymentInfo(id string) (*Payment, error) {
 	r := &HttpRequest{
-		Method:    "GET",
-		Path:      "/payments/" + id,
-		ShopId:    y.ShopId,
-		SecretKey: y.SecretKey,
+		Method:     "GET",
+		Path:       "/payments/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
 	}
 
 	bytes, err := r.SendRequest()
@@ -210,20 +262,15 @@ func (y *Yandex) GetPaymentInfo(id string) (*Payment, error) {
 }
 
 func (y *Yandex) ConfirmPayment(idempKey, id string, req *PaymentConfirmationRequest) (*Payment, error) {
-	q, err := query.Values(req)
-
-	if err != nil {
-		log.Printf("Failed creating query: %v\n", err)
-		return nil, err
-	}
-
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/payments/" + id + "/capture",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		ShopId:         y.ShopId,
 		SecretKey:      y.SecretKey,
 		IdempotenceKey: idempKey,
-		Data:           q,
+		Body:           req,
 	}
 
 	bytes, err := r.SendRequest()
@@ -246,6 +293,8 @@ func (y *Yandex) CancelPayment(idempKey, id string) (*Payment, error) {
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/payments/" + id + "/cancel",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		ShopId:         y.ShopId,
 		SecretKey:      y.SecretKey,
 		IdempotenceKey: idempKey,
@@ -267,124 +316,154 @@ func (y *Yandex) CancelPayment(idempKey, id string) (*Payment, error) {
 	return res, nil
 }
 
+// Deprecated: Alfa-Click is no longer supported by the API.
 func (r *PaymentRequest) WithAlfaBank(login string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:  "alfabank",
+	return r.withMethod(&PaymentMethod{
+		Type:  PaymentMethodAlfaBank,
 		Login: login,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithPhoneBalance(phone string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:  "mobile_balance",
+	return r.withMethod(&PaymentMethod{
+		Type:  PaymentMethodMobileBalance,
 		Phone: phone,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithBankCard(card *Card) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "bank_card",
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodBankCard,
 		Card: card,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithPartialPayment() *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "installments",
-	}
-
-	return r
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodInstallments,
+	})
 }
 
 func (r *PaymentRequest) WithCash(phone string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:  "cash",
+	return r.withMethod(&PaymentMethod{
+		Type:  PaymentMethodCash,
 		Phone: phone,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithSberbankB2B(purpose string, data *VATData) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:           "b2b_sberbank",
+	return r.withMethod(&PaymentMethod{
+		Type:           PaymentMethodB2BSberbank,
 		PaymentPurpose: purpose,
 		VATData:        data,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithSberbank(phone string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:  "sberbank",
+	return r.withMethod(&PaymentMethod{
+		Type:  PaymentMethodSberbank,
 		Phone: phone,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithTinkoffBank() *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "tinkoff_bank",
-	}
-
-	return r
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodTinkoffBank,
+	})
 }
 
+// Deprecated: use WithYooMoney.
 func (r *PaymentRequest) WithYandexMoney() *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "yandex_money",
-	}
-
-	return r
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodYandexMoney,
+	})
 }
 
 func (r *PaymentRequest) WithApplePay(data string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:        "apple_pay",
+	return r.withMethod(&PaymentMethod{
+		Type:        PaymentMethodApplePay,
 		PaymentData: data,
-	}
-
-	return r
+	})
 }
 
 func (r *PaymentRequest) WithGooglePay(token string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:               "google_pay",
+	return r.withMethod(&PaymentMethod{
+		Type:               PaymentMethodGooglePay,
 		PaymentMethodToken: token,
-	}
-
-	return r
+	})
 }
 
+// Deprecated: QIWI Wallet is no longer supported by the API.
 func (r *PaymentRequest) WithQiwi(phone string) *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type:  "qiwi",
+	return r.withMethod(&PaymentMethod{
+		Type:  PaymentMethodQiwi,
 		Phone: phone,
-	}
-
-	return r
+	})
 }
 
+// Deprecated: WeChat Pay is no longer supported by the API.
 func (r *PaymentRequest) WithWeChat() *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "wechat",
-	}
-
-	return r
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodWeChat,
+	})
 }
 
+// Deprecated: Webmoney is no longer supported by the API.
 func (r *PaymentRequest) WithWebmoney() *PaymentRequest {
-	r.PaymentMethodData = &PaymentMethod{
-		Type: "webmoney",
-	}
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodWebmoney,
+	})
+}
+
+func (r *PaymentRequest) WithSBP() *PaymentRequest {
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodSBP,
+	})
+}
+
+func (r *PaymentRequest) WithYooMoney() *PaymentRequest {
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodYooMoney,
+	})
+}
+
+func (r *PaymentRequest) WithSberLoan() *PaymentRequest {
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodSberLoan,
+	})
+}
+
+func (r *PaymentRequest) WithSberBNPL() *PaymentRequest {
+	return r.withMethod(&PaymentMethod{
+		Type: PaymentMethodSberBNPL,
+	})
+}
+
+func (r *PaymentRequest) WithElectronicCertificate(card *Card, cert *ElectronicCertificate, articles []*CertificateArticle) *PaymentRequest {
+	return r.withMethod(&PaymentMethod{
+		Type:                  PaymentMethodElectronicCertificate,
+		Card:                  card,
+		ElectronicCertificate: cert,
+		Articles:              articles,
+	})
+}
+
+// WithPaymentMethod sets a method built by hand, for example of a type
+// added with RegisterPaymentMethod, and checks it like the other builders.
+func (r *PaymentRequest) WithPaymentMethod(method *PaymentMethod) *PaymentRequest {
+	return r.withMethod(method)
+}
+
+// Err returns the error found by the last payment method builder, such
+// as WithBankCard or WithSberbankB2B, in its arguments. CreatePayment
+// returns it without sending the request.
+func (r *PaymentRequest) Err() error {
+	return r.methodErr
+}
+
+func (r *PaymentRequest) withMethod(method *PaymentMethod) *PaymentRequest {
+	r.PaymentMethodData = method
+	r.methodErr = method.Validate()
 
 	return r
 }
@@ -0,0 +1,327 @@
+package yandex
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	PayoutStatusPending   string = "pending"
+	PayoutStatusSucceeded string = "succeeded"
+	PayoutStatusCanceled  string = "canceled"
+)
+
+const (
+	PayoutDestinationBankCard string = "bank_card"
+	PayoutDestinationSBP      string = "sbp"
+	PayoutDestinationYooMoney string = "yoo_money"
+)
+
+const (
+	CancellationPartyPayoutNetwork string = "payout_network"
+)
+
+const (
+	CancellationReasonOneTimeLimitExceeded  string = "one_time_limit_exceeded"
+	CancellationReasonPeriodicLimitExceeded string = "periodic_limit_exceeded"
+	CancellationReasonRejectedByPayee       string = "rejected_by_payee"
+	CancellationReasonRecipientNotFound     string = "recipient_not_found"
+	CancellationReasonRecipientCheckFailed  string = "recipient_check_failed"
+)
+
+var ErrNoAgentCredentials = errors.New("yandex: AgentId and AgentSecretKey are required for payouts")
+
+var walletPattern = regexp.MustCompile(`^[0-9]{11,33}$`)
+
+// PayoutCard is sent with the card number only, the other fields are
+// filled by the API in responses.
+type PayoutCard struct {
+	Number        string `json:"number,omitempty"`
+	BIN           string `json:"first6,omitempty"`
+	LastFour      string `json:"last4,omitempty"`
+	CardType      string `json:"card_type,omitempty"`
+	IssuerCountry string `json:"issuer_country,omitempty"`
+	IssuerName    string `json:"issuer_name,omitempty"`
+}
+
+type PayoutDestination struct {
+	Type             string      `json:"type"`
+	Card             *PayoutCard `json:"card,omitempty"`
+	Phone            string      `json:"phone,omitempty"`
+	BankId           string      `json:"bank_id,omitempty"`
+	RecipientChecked bool        `json:"recipient_checked,omitempty"`
+	AccountNumber    string      `json:"account_number,omitempty"`
+}
+
+type PayoutDeal struct {
+	Id string `json:"id"`
+}
+
+type Payout struct {
+	Id                  string                 `json:"id"`
+	Amount              *Amount                `json:"amount"`
+	Status              string                 `json:"status"`
+	PayoutDestination   *PayoutDestination     `json:"payout_destination"`
+	Description         string                 `json:"description,omitempty"`
+	CreatedAt           string                 `json:"created_at"`
+	SucceededAt         string                 `json:"succeeded_at,omitempty"`
+	Deal                *PayoutDeal            `json:"deal,omitempty"`
+	CancellationDetails *CancellationDetails   `json:"cancellation_details,omitempty"`
+	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+	Test                bool                   `json:"test"`
+}
+
+type PayoutRequest struct {
+	Amount                *Amount                `json:"amount"`
+	PayoutToken           string                 `json:"payout_token,omitempty"`
+	PayoutDestinationData *PayoutDestination     `json:"payout_destination_data,omitempty"`
+	Description           string                 `json:"description,omitempty"`
+	Deal                  *PayoutDeal            `json:"deal,omitempty"`
+	PersonalData          []*PersonalDataRef     `json:"personal_data,omitempty"`
+	SelfEmployed          *PayoutSelfEmployed    `json:"self_employed,omitempty"`
+	ReceiptData           *PayoutReceiptData     `json:"receipt_data,omitempty"`
+	Metadata              map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type PayoutsListRequest struct {
+	Status                string `url:"status,omitempty"`
+	PayoutDestinationType string `url:"payout_destination.type,omitempty"`
+	CreatedAtGte          string `url:"created_at.gte,omitempty"`
+	CreatedAtGt           string `url:"created_at.gt,omitempty"`
+	CreatedAtLte          string `url:"created_at.lte,omitempty"`
+	CreatedAtLt           string `url:"created_at.lt,omitempty"`
+	Limit                 int    `url:"limit,omitempty"`
+	Cursor                string `url:"cursor,omitempty"`
+}
+
+type PayoutsListResponse struct {
+	Type       string    `json:"type"`
+	Items      []*Payout `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+func (y *Yandex) CreatePayout(idempKey string, req *PayoutRequest) (*Payout, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/payouts",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.AgentId,
+		SecretKey:      y.AgentSecretKey,
+		IdempotenceKey: idempKey,
+		Body:           req,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Payout{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetPayoutInfo(id string) (*Payout, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/payouts/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.AgentId,
+		SecretKey:  y.AgentSecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Payout{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetPayoutsList(req *PayoutsListRequest) (*PayoutsListResponse, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	q, err := query.Values(req)
+
+	if err != nil {
+		log.Printf("Failed creating query: %v\n", err)
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/payouts",
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.AgentId,
+		SecretKey:  y.AgentSecretKey,
+		Data:       q,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PayoutsListResponse{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *PayoutRequest) WithBankCard(number string) *PayoutRequest {
+	r.PayoutDestinationData = &PayoutDestination{
+		Type: PayoutDestinationBankCard,
+		Card: &PayoutCard{
+			Number: number,
+		},
+	}
+
+	return r
+}
+
+func (r *PayoutRequest) WithSBP(phone, bankId string) *PayoutRequest {
+	r.PayoutDestinationData = &PayoutDestination{
+		Type:   PayoutDestinationSBP,
+		Phone:  phone,
+		BankId: bankId,
+	}
+
+	return r
+}
+
+func (r *PayoutRequest) WithYooMoney(accountNumber string) *PayoutRequest {
+	r.PayoutDestinationData = &PayoutDestination{
+		Type:          PayoutDestinationYooMoney,
+		AccountNumber: accountNumber,
+	}
+
+	return r
+}
+
+func (r *PayoutRequest) WithDeal(id string) *PayoutRequest {
+	r.Deal = &PayoutDeal{
+		Id: id,
+	}
+
+	return r
+}
+
+func (r *PayoutRequest) Validate() error {
+	if r.Amount == nil || !r.Amount.Value.IsPositive() {
+		return newValidationError("amount.value", "Amount must be greater than zero")
+	}
+
+	if !r.Amount.Value.Round(2).Equal(r.Amount.Value) {
+		return newValidationError("amount.value", "Amount must have at most two decimal places")
+	}
+
+	if (len(r.PayoutToken) > 0) == (r.PayoutDestinationData != nil) {
+		return newValidationError("payout_destination_data", "Exactly one of payout_token and payout_destination_data must be specified")
+	}
+
+	if len([]rune(r.Description)) > 128 {
+		return newValidationError("description", "Description must contain at most 128 characters")
+	}
+
+	if (r.SelfEmployed == nil) != (r.ReceiptData == nil) {
+		return newValidationError("receipt_data", "Self-employed and receipt data must be specified together")
+	}
+
+	if r.SelfEmployed != nil {
+		if len(r.SelfEmployed.Id) == 0 {
+			return newValidationError("self_employed.id", "Self-employed id is required")
+		}
+
+		if err := r.ReceiptData.Validate(r.Amount); err != nil {
+			return err
+		}
+	}
+
+	d := r.PayoutDestinationData
+
+	if d == nil {
+		return nil
+	}
+
+	switch d.Type {
+	case PayoutDestinationBankCard:
+		if d.Card == nil || !cardPattern.MatchString(d.Card.Number) {
+			return newValidationError("payout_destination_data.card.number", "Card number must contain 12 to 19 digits")
+		}
+	case PayoutDestinationSBP:
+		if !phonePattern.MatchString(d.Phone) {
+			return newValidationError("payout_destination_data.phone", "Phone must be in ITU-T E.164 format without plus sign")
+		}
+
+		if len(d.BankId) == 0 {
+			return newValidationError("payout_destination_data.bank_id", "Bank id is required for sbp payouts")
+		}
+	case PayoutDestinationYooMoney:
+		if !walletPattern.MatchString(d.AccountNumber) {
+			return newValidationError("payout_destination_data.account_number", "Wallet account number must contain 11 to 33 digits")
+		}
+	default:
+		return newValidationError("payout_destination_data.type", "Payout destination must be bank_card, sbp or yoo_money")
+	}
+
+	return nil
+}
+
+func (p *Payout) IsSucceeded() bool {
+	return p.Status == PayoutStatusSucceeded
+}
+
+func (p *Payout) IsCanceled() bool {
+	return p.Status == PayoutStatusCanceled
+}
+
+func (n *Notification) Payout() (*Payout, error) {
+	res := &Payout{}
+
+	if err := json.Unmarshal(n.Object, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,99 @@
+package yandex
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type authDoer struct {
+	auth string
+}
+
+func (d *authDoer) Do(req *fasthttp.Request, res *fasthttp.Response) error {
+	d.auth = string(req.Header.Peek("Authorization"))
+
+	res.SetStatusCode(200)
+	res.SetBodyString(`{"id":"po1","status":"pending"}`)
+
+	return nil
+}
+
+func TestPayoutRequestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   *PayoutRequest
+		param string
+	}{
+		{"card", (&PayoutRequest{Amount: rub("100")}).WithBankCard("5555555555554477"), ""},
+		{"sbp", (&PayoutRequest{Amount: rub("100")}).WithSBP("79000000000", "100000000111"), ""},
+		{"wallet", (&PayoutRequest{Amount: rub("100")}).WithYooMoney("41001614575714"), ""},
+		{"token", &PayoutRequest{Amount: rub("100"), PayoutToken: "token"}, ""},
+		{"self-employed", &PayoutRequest{Amount: rub("100"), PayoutToken: "token", SelfEmployed: &PayoutSelfEmployed{Id: "se1"}, ReceiptData: &PayoutReceiptData{ServiceName: "Delivery"}}, ""},
+		{"no amount", &PayoutRequest{PayoutToken: "token"}, "amount.value"},
+		{"fractional kopecks", &PayoutRequest{Amount: rub("1.005"), PayoutToken: "token"}, "amount.value"},
+		{"no destination", &PayoutRequest{Amount: rub("100")}, "payout_destination_data"},
+		{"token and destination", (&PayoutRequest{Amount: rub("100"), PayoutToken: "token"}).WithSBP("79000000000", "100000000111"), "payout_destination_data"},
+		{"card number", (&PayoutRequest{Amount: rub("100")}).WithBankCard("5555 5555"), "payout_destination_data.card.number"},
+		{"sbp phone", (&PayoutRequest{Amount: rub("100")}).WithSBP("+79000000000", "100000000111"), "payout_destination_data.phone"},
+		{"sbp bank", (&PayoutRequest{Amount: rub("100")}).WithSBP("79000000000", ""), "payout_destination_data.bank_id"},
+		{"short wallet", (&PayoutRequest{Amount: rub("100")}).WithYooMoney("4100161"), "payout_destination_data.account_number"},
+		{"wallet with letters", (&PayoutRequest{Amount: rub("100")}).WithYooMoney("41001abcdef5714"), "payout_destination_data.account_number"},
+		{"wallet with spaces", (&PayoutRequest{Amount: rub("100")}).WithYooMoney("4100 1614 5757 14"), "payout_destination_data.account_number"},
+		{"unknown destination", &PayoutRequest{Amount: rub("100"), PayoutDestinationData: &PayoutDestination{Type: "cash"}}, "payout_destination_data.type"},
+		{"receipt without self-employed", &PayoutRequest{Amount: rub("100"), PayoutToken: "token", ReceiptData: &PayoutReceiptData{ServiceName: "Delivery"}}, "receipt_data"},
+		{"receipt currency", &PayoutRequest{Amount: rub("100"), PayoutToken: "token", SelfEmployed: &PayoutSelfEmployed{Id: "se1"}, ReceiptData: &PayoutReceiptData{ServiceName: "Delivery", Amount: &Amount{Value: rub("100").Value, Currency: "USD"}}}, "receipt_data.amount.currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("Validate() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestCreatePayout(t *testing.T) {
+	d := &authDoer{}
+	req := (&PayoutRequest{Amount: rub("100")}).WithYooMoney("41001614575714")
+	y := &Yandex{ShopId: "shop", SecretKey: "shop_secret", HttpClient: d}
+
+	if _, err := y.CreatePayout("key", req); err != ErrNoAgentCredentials {
+		t.Errorf("CreatePayout() without agent = %v, want ErrNoAgentCredentials", err)
+	}
+
+	if _, err := y.GetPayoutInfo("po1"); err != ErrNoAgentCredentials {
+		t.Errorf("GetPayoutInfo() without agent = %v, want ErrNoAgentCredentials", err)
+	}
+
+	y.AgentId = "agent"
+	y.AgentSecretKey = "agent_secret"
+
+	p, err := y.CreatePayout("key", req)
+
+	if err != nil || p.Id != "po1" {
+		t.Fatalf("CreatePayout() = %v, %v", p, err)
+	}
+
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("agent:agent_secret")); d.auth != want {
+		t.Errorf("Authorization = %s, want %s", d.auth, want)
+	}
+
+	d.auth = ""
+
+	if _, err := y.CreatePayout("key", &PayoutRequest{Amount: rub("100")}); err == nil || len(d.auth) > 0 {
+		t.Errorf("CreatePayout() of invalid request = %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package yandex
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PersonalDataTypeSBPPayoutRecipient       string = "sbp_payout_recipient"
+	PersonalDataTypePayoutStatementRecipient string = "payout_statement_recipient"
+)
+
+const (
+	PersonalDataStatusWaitingForOperation string = "waiting_for_operation"
+	PersonalDataStatusActive              string = "active"
+	PersonalDataStatusCanceled            string = "canceled"
+)
+
+const DefaultBankDirectoryTTL = 24 * time.Hour
+
+var bicPattern = regexp.MustCompile(`^[0-9]{9}$`)
+
+var ErrBankNotFound = errors.New("yandex: bank isn't an SBP participant")
+
+type PersonalData struct {
+	Id                  string                 `json:"id"`
+	Type                string                 `json:"type"`
+	Status              string                 `json:"status"`
+	CancellationDetails *CancellationDetails   `json:"cancellation_details,omitempty"`
+	CreatedAt           string                 `json:"created_at"`
+	ExpiresAt           string                 `json:"expires_at,omitempty"`
+	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type PersonalDataRequest struct {
+	Type       string                 `json:"type"`
+	LastName   string                 `json:"last_name"`
+	FirstName  string                 `json:"first_name"`
+	MiddleName string                 `json:"middle_name,omitempty"`
+	Birthdate  string                 `json:"birthdate,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type PersonalDataRef struct {
+	Id string `json:"id"`
+}
+
+type SBPBank struct {
+	BankId string `json:"bank_id"`
+	Name   string `json:"name"`
+	BIC    string `json:"bic"`
+}
+
+type SBPBanksListResponse struct {
+	Type  string     `json:"type"`
+	Items []*SBPBank `json:"items"`
+}
+
+func (y *Yandex) CreatePersonalData(idempKey string, req *PersonalDataRequest) (*PersonalData, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/personal_data",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.AgentId,
+		SecretKey:      y.AgentSecretKey,
+		IdempotenceKey: idempKey,
+		Body:           req,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PersonalData{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetPersonalData(id string) (*PersonalData, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/personal_data/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.AgentId,
+		SecretKey:  y.AgentSecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PersonalData{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetSBPBanks() (*SBPBanksListResponse, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/sbp_banks",
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.AgentId,
+		SecretKey:  y.AgentSecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SBPBanksListResponse{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *PersonalDataRequest) Validate() error {
+	switch r.Type {
+	case PersonalDataTypeSBPPayoutRecipient:
+	case PersonalDataTypePayoutStatementRecipient:
+		if _, err := time.Parse("2006-01-02", r.Birthdate); err != nil {
+			return newValidationError("birthdate", "Birthdate must be in YYYY-MM-DD format")
+		}
+	default:
+		return newValidationError("type", "Personal data type must be sbp_payout_recipient or payout_statement_recipient")
+	}
+
+	if len(r.LastName) == 0 || len([]rune(r.LastName)) > 200 {
+		return newValidationError("last_name", "Last name must contain 1 to 200 characters")
+	}
+
+	if len(r.FirstName) == 0 || len([]rune(r.FirstName)) > 200 {
+		return newValidationError("first_name", "First name must contain 1 to 200 characters")
+	}
+
+	if len([]rune(r.MiddleName)) > 200 {
+		return newValidationError("middle_name", "Middle name must contain at most 200 characters")
+	}
+
+	return nil
+}
+
+func (p *PersonalData) IsActive() bool {
+	return p.Status == PersonalDataStatusActive
+}
+
+func (r *PayoutRequest) WithPersonalData(ids ...string) *PayoutRequest {
+	for _, id := range ids {
+		r.PersonalData = append(r.PersonalData, &PersonalDataRef{
+			Id: id,
+		})
+	}
+
+	return r
+}
+
+type SBPBanksLister interface {
+	GetSBPBanks() (*SBPBanksListResponse, error)
+}
+
+// BankDirectory caches the list of SBP participants, which changes rarely,
+// so it can back a bank picker without hitting the API on every lookup.
+type BankDirectory struct {
+	Client SBPBanksLister
+	TTL    time.Duration
+
+	mu      sync.Mutex
+	banks   []*SBPBank
+	fetched time.Time
+}
+
+func NewBankDirectory(client SBPBanksLister) *BankDirectory {
+	return &BankDirectory{
+		Client: client,
+		TTL:    DefaultBankDirectoryTTL,
+	}
+}
+
+func (d *BankDirectory) Banks() ([]*SBPBank, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.banks != nil && time.Since(d.fetched) < d.TTL {
+		return d.banks, nil
+	}
+
+	res, err := d.Client.GetSBPBanks()
+
+	if err != nil {
+		if d.banks != nil {
+			log.Printf("Failed refreshing SBP banks, using cached list: %v\n", err)
+			return d.banks, nil
+		}
+
+		return nil, err
+	}
+
+	d.banks = res.Items
+	d.fetched = time.Now()
+
+	return d.banks, nil
+}
+
+func (d *BankDirectory) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.banks = nil
+}
+
+func (d *BankDirectory) FindByBIC(bic string) (*SBPBank, error) {
+	banks, err := d.Banks()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range banks {
+		if b.BIC == bic {
+			return b, nil
+		}
+	}
+
+	return nil, ErrBankNotFound
+}
+
+func (d *BankDirectory) FindById(id string) (*SBPBank, error) {
+	banks, err := d.Banks()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range banks {
+		if b.BankId == id {
+			return b, nil
+		}
+	}
+
+	return nil, ErrBankNotFound
+}
+
+// Search returns banks whose name contains the query ignoring case, or the
+// bank with the given BIC when the query is a BIC.
+func (d *BankDirectory) Search(q string) ([]*SBPBank, error) {
+	q = strings.TrimSpace(q)
+
+	if bicPattern.MatchString(q) {
+		b, err := d.FindByBIC(q)
+
+		if err == ErrBankNotFound {
+			return []*SBPBank{}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*SBPBank{b}, nil
+	}
+
+	banks, err := d.Banks()
+
+	if err != nil {
+		return nil, err
+	}
+
+	q = strings.ToLower(q)
+	res := []*SBPBank{}
+
+	for _, b := range banks {
+		if strings.Contains(strings.ToLower(b.Name), q) {
+			res = append(res, b)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package yandex
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultReceiptPollInterval = 2 * time.Second
+	DefaultReceiptPollTimeout  = time.Minute
+)
+
+var (
+	ErrReceiptCanceled = errors.New("yandex: receipt registration was canceled")
+	ErrReceiptTimeout  = errors.New("yandex: receipt wasn't registered in time")
+)
+
+// NewFinalSettlementReceipt builds the second receipt which has to be sent
+// when goods paid by full prepayment are handed over to the customer. Items
+// are copied from the original receipt and settled by the prepayment.
+func NewFinalSettlementReceipt(p *Payment, original *Receipt) (*ReceiptRequest, error) {
+	if p.Status != PaymentStatusSucceeded {
+		return nil, newValidationError("payment_id", "Final settlement receipt can be sent only for succeeded payment")
+	}
+
+	if original == nil || len(original.Items) == 0 {
+		return nil, newValidationError("items", "Original receipt items are required")
+	}
+
+	items := make([]*Item, len(original.Items))
+
+	for i, it := range original.Items {
+		if it.PaymentMode != PaymentModeFullPrepayment {
+			return nil, newValidationError("items.payment_mode", "Original receipt item must be full_prepayment, got "+string(it.PaymentMode))
+		}
+
+		item := *it
+		item.PaymentMode = PaymentModeFullPayment
+		items[i] = &item
+	}
+
+	customer := original.Customer
+
+	if customer == nil {
+		customer = &Customer{
+			Email: original.Email,
+			Phone: original.Phone,
+		}
+	}
+
+	req := &ReceiptRequest{
+		Type:                      ReceiptTypePayment,
+		PaymentId:                 p.Id,
+		Customer:                  customer,
+		Items:                     items,
+		TaxSystemCode:             original.TaxSystemCode,
+		Send:                      true,
+		OnBehalfOf:                original.OnBehalfOf,
+		ReceiptIndustryDetails:    original.ReceiptIndustryDetails,
+		ReceiptOperationalDetails: original.ReceiptOperationalDetails,
+	}
+
+	total := itemsTotal(items)
+
+	if p.Amount != nil && total.GreaterThan(p.Amount.Value) {
+		return nil, newValidationError("items", "Items total "+total.StringFixed(2)+" exceeds payment amount "+p.Amount.Value.StringFixed(2))
+	}
+
+	currency := items[0].Amount.Currency
+
+	if p.Amount != nil {
+		currency = p.Amount.Currency
+	}
+
+	req.AddSettlement(SettlementTypePrepayment, &Amount{
+		Value:    total,
+		Currency: currency,
+	})
+
+	return req, req.Validate()
+}
+
+// ReceiptTracker sends receipts and polls them until the fiscal provider
+// registers or rejects them.
+type ReceiptTracker struct {
+	Client   Receipts
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+func NewReceiptTracker(client Receipts) *ReceiptTracker {
+	return &ReceiptTracker{
+		Client:   client,
+		Interval: DefaultReceiptPollInterval,
+		Timeout:  DefaultReceiptPollTimeout,
+	}
+}
+
+func (t *ReceiptTracker) Register(idempKey string, req *ReceiptRequest) (*Receipt, error) {
+	receipt, err := t.Client.CreateReceipt(idempKey, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Wait(receipt)
+}
+
+// Wait returns the receipt once it succeeded. The last known state is
+// returned together with ErrReceiptCanceled or ErrReceiptTimeout.
+func (t *ReceiptTracker) Wait(receipt *Receipt) (*Receipt, error) {
+	interval := t.Interval
+
+	if interval <= 0 {
+		interval = DefaultReceiptPollInterval
+	}
+
+	timeout := t.Timeout
+
+	if timeout <= 0 {
+		timeout = DefaultReceiptPollTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		switch receipt.Status {
+		case ReceiptStatusSucceeded:
+			return receipt, nil
+		case ReceiptStatusCanceled:
+			return receipt, ErrReceiptCanceled
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return receipt, ErrReceiptTimeout
+		}
+
+		time.Sleep(interval)
+
+		res, err := t.Client.GetReceiptInfo(receipt.Id)
+
+		if err != nil {
+			return receipt, err
+		}
+
+		receipt = res
+	}
+}
@@ -0,0 +1,155 @@
+package yandex
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func prepaidReceipt() *Receipt {
+	r := newTestReceipt()
+
+	for _, it := range r.Items {
+		it.PaymentMode = PaymentModeFullPrepayment
+	}
+
+	return r
+}
+
+func TestNewFinalSettlementReceipt(t *testing.T) {
+	p := &Payment{Id: "p1", Status: PaymentStatusSucceeded, Amount: rub("351")}
+	original := prepaidReceipt()
+
+	req, err := NewFinalSettlementReceipt(p, original)
+
+	if err != nil {
+		t.Fatalf("NewFinalSettlementReceipt() = %v", err)
+	}
+
+	if req.PaymentId != "p1" || req.Type != ReceiptTypePayment || !req.Send {
+		t.Errorf("NewFinalSettlementReceipt() = %+v", req)
+	}
+
+	for i, it := range req.Items {
+		if it.PaymentMode != PaymentModeFullPayment {
+			t.Errorf("item %d payment mode = %s", i, it.PaymentMode)
+		}
+	}
+
+	if original.Items[0].PaymentMode != PaymentModeFullPrepayment {
+		t.Errorf("original item payment mode = %s", original.Items[0].PaymentMode)
+	}
+
+	if len(req.Settlements) != 1 || req.Settlements[0].Type != SettlementTypePrepayment || !req.Settlements[0].Amount.Value.Equal(qty("351")) {
+		t.Errorf("settlements = %+v", req.Settlements)
+	}
+}
+
+func TestNewFinalSettlementReceiptErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		amount   string
+		original func() *Receipt
+		param    string
+	}{
+		{"pending payment", PaymentStatusPending, "351", prepaidReceipt, "payment_id"},
+		{"no receipt", PaymentStatusSucceeded, "351", func() *Receipt { return nil }, "items"},
+		{"no items", PaymentStatusSucceeded, "351", func() *Receipt { return NewReceipt("user@example.com", "") }, "items"},
+		{"not prepaid", PaymentStatusSucceeded, "351", newTestReceipt, "items.payment_mode"},
+		{"over amount", PaymentStatusSucceeded, "350", prepaidReceipt, "items"},
+		{"no customer", PaymentStatusSucceeded, "351", func() *Receipt {
+			r := prepaidReceipt()
+			r.Customer = nil
+
+			return r
+		}, "customer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Payment{Id: "p1", Status: tt.status, Amount: rub(tt.amount)}
+
+			_, err := NewFinalSettlementReceipt(p, tt.original())
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("NewFinalSettlementReceipt() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+type receiptsStub struct {
+	statuses  []string
+	createErr error
+	getErr    error
+	polls     int
+}
+
+func (s *receiptsStub) CreateReceipt(idempKey string, req *ReceiptRequest) (*Receipt, error) {
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+
+	return &Receipt{Id: "r1", Status: ReceiptStatusPending}, nil
+}
+
+func (s *receiptsStub) GetReceiptInfo(id string) (*Receipt, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+
+	status := s.statuses[len(s.statuses)-1]
+
+	if s.polls < len(s.statuses) {
+		status = s.statuses[s.polls]
+	}
+
+	s.polls++
+
+	return &Receipt{Id: id, Status: status}, nil
+}
+
+func TestReceiptTrackerRegister(t *testing.T) {
+	failure := errors.New("connection reset")
+
+	tests := []struct {
+		name   string
+		stub   *receiptsStub
+		status string
+		err    error
+	}{
+		{"succeeded", &receiptsStub{statuses: []string{ReceiptStatusPending, ReceiptStatusSucceeded}}, ReceiptStatusSucceeded, nil},
+		{"canceled", &receiptsStub{statuses: []string{ReceiptStatusCanceled}}, ReceiptStatusCanceled, ErrReceiptCanceled},
+		{"timeout", &receiptsStub{statuses: []string{ReceiptStatusPending}}, ReceiptStatusPending, ErrReceiptTimeout},
+		{"poll error", &receiptsStub{getErr: failure}, ReceiptStatusPending, failure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewReceiptTracker(tt.stub)
+			tracker.Interval = time.Millisecond
+			tracker.Timeout = 20 * time.Millisecond
+
+			receipt, err := tracker.Register("key", &ReceiptRequest{})
+
+			if err != tt.err {
+				t.Fatalf("Register() error = %v, want %v", err, tt.err)
+			}
+
+			if receipt == nil || receipt.Status != tt.status {
+				t.Errorf("Register() = %+v, want status %s", receipt, tt.status)
+			}
+		})
+	}
+
+	stub := &receiptsStub{createErr: failure}
+
+	if receipt, err := NewReceiptTracker(stub).Register("key", &ReceiptRequest{}); receipt != nil || err != failure {
+		t.Errorf("Register() = %+v, %v, want create error", receipt, err)
+	}
+
+	if stub.polls != 0 {
+		t.Errorf("polls = %d after failed create", stub.polls)
+	}
+}
@@ -0,0 +1,123 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/skip2/go-qrcode"
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelMedium
+	LevelQuartile
+	LevelHigh
+)
+
+const DefaultSize int = 256
+
+var ErrNoConfirmationData = errors.New("qr: payment has no qr confirmation data")
+
+func Data(p *yandex.Payment) (string, error) {
+	if p == nil || p.Confirmation == nil || p.Confirmation.Type != yandex.ConfirmationQR || len(p.Confirmation.ConfirmationData) == 0 {
+		return "", ErrNoConfirmationData
+	}
+
+	return p.Confirmation.ConfirmationData, nil
+}
+
+func PNG(p *yandex.Payment, size int, level Level) ([]byte, error) {
+	data, err := Data(p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodePNG(data, size, level)
+}
+
+func SVG(p *yandex.Payment, size int, level Level) ([]byte, error) {
+	data, err := Data(p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeSVG(data, size, level)
+}
+
+func EncodePNG(data string, size int, level Level) ([]byte, error) {
+	q, err := newCode(data, level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return q.PNG(size)
+}
+
+func EncodeSVG(data string, size int, level Level) ([]byte, error) {
+	q, err := newCode(data, level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	bitmap := q.Bitmap()
+	n := len(bitmap)
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, n, n)
+
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			start := x
+
+			for x < len(row) && row[x] {
+				x++
+			}
+
+			fmt.Fprintf(buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes(), nil
+}
+
+func newCode(data string, level Level) (*qrcode.QRCode, error) {
+	var l qrcode.RecoveryLevel
+
+	switch level {
+	case LevelLow:
+		l = qrcode.Low
+	case LevelMedium:
+		l = qrcode.Medium
+	case LevelQuartile:
+		l = qrcode.High
+	case LevelHigh:
+		l = qrcode.Highest
+	default:
+		return nil, fmt.Errorf("qr: unknown error correction level %d", level)
+	}
+
+	return qrcode.New(data, l)
+}
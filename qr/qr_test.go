@@ -0,0 +1,114 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+const testData string = "https://qr.nspk.ru/AS1000670LSS7DN18SJQDNP4B05KLJL2?type=01&bank=100000000001&sum=10000&cur=RUB&crc=C08B"
+
+func TestData(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *yandex.Payment
+		ok   bool
+	}{
+		{"qr", &yandex.Payment{Confirmation: &yandex.Confirmation{Type: yandex.ConfirmationQR, ConfirmationData: testData}}, true},
+		{"nil payment", nil, false},
+		{"no confirmation", &yandex.Payment{}, false},
+		{"redirect", &yandex.Payment{Confirmation: &yandex.Confirmation{Type: yandex.ConfirmationRedirect, ConfirmationUrl: "https://pay"}}, false},
+		{"qr without data", &yandex.Payment{Confirmation: &yandex.Confirmation{Type: yandex.ConfirmationQR}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Data(tt.p)
+
+			if !tt.ok {
+				if err != ErrNoConfirmationData {
+					t.Errorf("Data() = %q, %v, want ErrNoConfirmationData", data, err)
+				}
+
+				return
+			}
+
+			if err != nil || data != testData {
+				t.Errorf("Data() = %q, %v", data, err)
+			}
+
+			if _, err := PNG(tt.p, 0, LevelMedium); err != nil {
+				t.Errorf("PNG() = %v", err)
+			}
+
+			if _, err := SVG(tt.p, 0, LevelMedium); err != nil {
+				t.Errorf("SVG() = %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	tests := []struct {
+		size int
+		want int
+	}{
+		{0, DefaultSize},
+		{-1, DefaultSize},
+		{512, 512},
+	}
+
+	for _, tt := range tests {
+		b, err := EncodePNG(testData, tt.size, LevelHigh)
+
+		if err != nil {
+			t.Fatalf("EncodePNG() = %v", err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(b))
+
+		if err != nil {
+			t.Fatalf("png.Decode() = %v", err)
+		}
+
+		if w := img.Bounds().Dx(); w != tt.want {
+			t.Errorf("size %d: image is %d pixels wide, want %d", tt.size, w, tt.want)
+		}
+	}
+}
+
+func TestEncodeSVG(t *testing.T) {
+	b, err := EncodeSVG(testData, 0, LevelLow)
+
+	if err != nil {
+		t.Fatalf("EncodeSVG() = %v", err)
+	}
+
+	svg := string(b)
+
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("EncodeSVG() = %s", svg)
+	}
+
+	if !strings.Contains(svg, `width="256" height="256"`) {
+		t.Error("EncodeSVG() doesn't use the default size")
+	}
+
+	// The finder pattern starts after the quiet zone of four modules.
+	if !strings.Contains(svg, `d="M4 4h7v1h-7z`) {
+		t.Error("EncodeSVG() doesn't start with the finder pattern")
+	}
+}
+
+func TestUnknownLevel(t *testing.T) {
+	if _, err := EncodePNG(testData, 0, Level(7)); err == nil {
+		t.Error("EncodePNG() with unknown level succeeded")
+	}
+
+	if _, err := EncodeSVG(testData, 0, Level(-1)); err == nil {
+		t.Error("EncodeSVG() with unknown level succeeded")
+	}
+}
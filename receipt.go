@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"log"
 
-	"github.com/google/go-querystring/query"
 	"github.com/shopspring/decimal"
 )
 
@@ -22,72 +21,80 @@ type Supplier struct {
 }
 
 type Item struct {
-	Description              string          `json:"description"`
-	Quantity                 decimal.Decimal `json:"quantity"`
-	Amount                   *Amount         `json:"amount"`
-	VATCode                  uint32          `json:"vat_code"`
-	PaymentSubject           string          `json:"payment_subject,omitempty"`
-	PaymentMode              string          `json:"payment_mode,omitempty"`
-	ProductCode              string          `json:"product_code,omitempty"`
-	CountryOfOriginCode      string          `json:"country_of_origin_code,omitempty"`
-	CustomsDeclarationNumber string          `json:"customs_declaration_number,omitempty"`
-	Excise                   string          `json:"excise,omitempty"`
-	Supplier                 *Supplier       `json:"supplier,omitempty"`
-	AgentType                string          `json:"agent_type,omitempty"`
+	Description                   string             `json:"description"`
+	Quantity                      decimal.Decimal    `json:"quantity"`
+	Amount                        *Amount            `json:"amount"`
+	VATCode                       VATCode            `json:"vat_code"`
+	PaymentSubject                PaymentSubject     `json:"payment_subject,omitempty"`
+	PaymentMode                   PaymentMode        `json:"payment_mode,omitempty"`
+	ProductCode                   string             `json:"product_code,omitempty"`
+	CountryOfOriginCode           string             `json:"country_of_origin_code,omitempty"`
+	CustomsDeclarationNumber      string             `json:"customs_declaration_number,omitempty"`
+	Excise                        string             `json:"excise,omitempty"`
+	Supplier                      *Supplier          `json:"supplier,omitempty"`
+	AgentType                     AgentType          `json:"agent_type,omitempty"`
+	Measure                       Measure            `json:"measure,omitempty"`
+	MarkQuantity                  *MarkQuantity      `json:"mark_quantity,omitempty"`
+	MarkCodeInfo                  *MarkCodeInfo      `json:"mark_code_info,omitempty"`
+	MarkMode                      string             `json:"mark_mode,omitempty"`
+	PaymentSubjectIndustryDetails []*IndustryDetails `json:"payment_subject_industry_details,omitempty"`
 }
 
 type Settlement struct {
-	Type   string  `json:"type"`
-	Amount *Amount `json:"amount"`
+	Type   SettlementType `json:"type"`
+	Amount *Amount        `json:"amount"`
 }
 
 type Receipt struct {
-	Id                   string        `json:"id,omitempty"`
-	Type                 string        `json:"type,omitempty"`
-	PaymentId            string        `json:"payment_id,omitempty"`
-	RefundId             string        `json:"refund_id,omitempty"`
-	Status               string        `json:"status,omitempty"`
-	FiscalDocumentNumber string        `json:"fiscal_document_number,omitempty"`
-	FiscalStorageNumber  string        `json:"fiscal_storage_number,omitempty"`
-	FiscalAttribute      string        `json:"fiscal_attribute,omitempty"`
-	RegisteredAt         string        `json:"registered_at,omitempty"`
-	FiscalProviderId     string        `json:"fiscal_provider_id,omitempty"`
-	Settlements          []*Settlement `json:"settlements,omitempty"`
-	Customer             *Customer     `json:"customer,omitempty"`
-	Items                []*Item       `json:"items"`
-	TaxSystemCode        uint32        `json:"tax_system_code,omitempty"`
-	Phone                string        `json:"phone,omitempty"`
-	Email                string        `json:"email,omitempty"`
-	OnBehalfOf           string        `json:"on_behalf_of,omitempty"`
+	Id                        string              `json:"id,omitempty"`
+	Type                      string              `json:"type,omitempty"`
+	PaymentId                 string              `json:"payment_id,omitempty"`
+	RefundId                  string              `json:"refund_id,omitempty"`
+	Status                    string              `json:"status,omitempty"`
+	FiscalDocumentNumber      string              `json:"fiscal_document_number,omitempty"`
+	FiscalStorageNumber       string              `json:"fiscal_storage_number,omitempty"`
+	FiscalAttribute           string              `json:"fiscal_attribute,omitempty"`
+	RegisteredAt              string              `json:"registered_at,omitempty"`
+	FiscalProviderId          string              `json:"fiscal_provider_id,omitempty"`
+	Settlements               []*Settlement       `json:"settlements,omitempty"`
+	Customer                  *Customer           `json:"customer,omitempty"`
+	Items                     []*Item             `json:"items"`
+	TaxSystemCode             TaxSystemCode       `json:"tax_system_code,omitempty"`
+	Phone                     string              `json:"phone,omitempty"`
+	Email                     string              `json:"email,omitempty"`
+	OnBehalfOf                string              `json:"on_behalf_of,omitempty"`
+	ReceiptIndustryDetails    []*IndustryDetails  `json:"receipt_industry_details,omitempty"`
+	ReceiptOperationalDetails *OperationalDetails `json:"receipt_operational_details,omitempty"`
 }
 
 type ReceiptRequest struct {
-	Type          string        `json:"type"`
-	PaymentId     string        `json:"payment_id,omitempty"`
-	RefundId      string        `json:"refund_id,omitempty"`
-	Customer      *Customer     `json:"customer"`
-	Items         []*Item       `json:"items"`
-	TaxSystemCode uint32        `json:"tax_system_code,omitempty"`
-	Send          bool          `json:"send"`
-	Settlements   []*Settlement `json:"settlements"`
-	OnBehalfOf    string        `json:"on_behalf_of,omitempty"`
+	Type                      string              `json:"type"`
+	PaymentId                 string              `json:"payment_id,omitempty"`
+	RefundId                  string              `json:"refund_id,omitempty"`
+	Customer                  *Customer           `json:"customer"`
+	Items                     []*Item             `json:"items"`
+	TaxSystemCode             TaxSystemCode       `json:"tax_system_code,omitempty"`
+	Send                      bool                `json:"send"`
+	Settlements               []*Settlement       `json:"settlements"`
+	OnBehalfOf                string              `json:"on_behalf_of,omitempty"`
+	ReceiptIndustryDetails    []*IndustryDetails  `json:"receipt_industry_details,omitempty"`
+	ReceiptOperationalDetails *OperationalDetails `json:"receipt_operational_details,omitempty"`
 }
 
 func (y *Yandex) CreateReceipt(idempKey string, req *ReceiptRequest) (*Receipt, error) {
-	q, err := query.Values(req)
-
-	if err != nil {
-		log.Printf("Failed creating query: %v\n", err)
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/receipts",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		ShopId:         y.ShopId,
 		SecretKey:      y.SecretKey,
 		IdempotenceKey: idempKey,
-		Data:           q,
+		Body:           req,
 	}
 
 	bytes, err := r.SendRequest()
@@ -108,10 +115,12 @@ func (y *Yandex) CreateReceipt(idempKey string, req *ReceiptRequest) (*Receipt,
 
 func (y *Yandex) GetReceiptInfo(id string) (*Receipt, error) {
 	r := &HttpRequest{
-		Method:    "GET",
-		Path:      "/receipts/" + id,
-		ShopId:    y.ShopId,
-		SecretKey: y.SecretKey,
+		Method:     "GET",
+		Path:       "/receipts/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
 	}
 
 	bytes, err := r.SendRequest()
@@ -3,48 +3,50 @@ package yandex
 import (
 	"encoding/json"
 	"log"
-
-	"github.com/google/go-querystring/query"
 )
 
 type Source struct {
-	AccountId string  `json:"account_id"`
-	Amount    *Amount `json:"amount"`
+	AccountId         string  `json:"account_id"`
+	Amount            *Amount `json:"amount"`
+	PlatformFeeAmount *Amount `json:"platform_fee_amount,omitempty"`
 }
 
 type Refund struct {
-	Id          string    `json:"id"`
-	PaymentId   string    `json:"payment_id"`
-	Status      string    `json:"status"`
-	CreatedAt   string    `json:"created_at"`
-	Amount      *Amount   `json:"amount"`
-	Description string    `json:"description,omitempty"`
-	Sources     []*Source `json:"sources,omitempty"`
+	Id          string      `json:"id"`
+	PaymentId   string      `json:"payment_id"`
+	Status      string      `json:"status"`
+	CreatedAt   string      `json:"created_at"`
+	Amount      *Amount     `json:"amount"`
+	Description string      `json:"description,omitempty"`
+	Sources     []*Source   `json:"sources,omitempty"`
+	Deal        *RefundDeal `json:"deal,omitempty"`
 }
 
 type RefundRequest struct {
-	PaymentId   string    `json:"payment_id"`
-	Amount      *Amount   `json:"amount"`
-	Description string    `json:"description,omitempty"`
-	Receipt     *Receipt  `json:"receipt,omitempty"`
-	Sources     []*Source `json:"sources,omitempty"`
+	PaymentId   string      `json:"payment_id"`
+	Amount      *Amount     `json:"amount"`
+	Description string      `json:"description,omitempty"`
+	Receipt     *Receipt    `json:"receipt,omitempty"`
+	Sources     []*Source   `json:"sources,omitempty"`
+	Deal        *RefundDeal `json:"deal,omitempty"`
 }
 
 func (y *Yandex) CreateRefund(idempKey string, req *RefundRequest) (*Refund, error) {
-	q, err := query.Values(req)
-
-	if err != nil {
-		log.Printf("Failed creating query: %v\n", err)
-		return nil, err
+	if req.Receipt != nil {
+		if err := req.Receipt.ValidateFor(req.Amount); err != nil {
+			return nil, err
+		}
 	}
 
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/refunds",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		ShopId:         y.ShopId,
 		SecretKey:      y.SecretKey,
 		IdempotenceKey: idempKey,
-		Data:           q,
+		Body:           req,
 	}
 
 	bytes, err := r.SendRequest()
@@ -65,10 +67,12 @@ func (y *Yandex) CreateRefund(idempKey string, req *RefundRequest) (*Refund, err
 
 func (y *Yandex) GetRefundInfo(id string) (*Refund, error) {
 	r := &HttpRequest{
-		Method:    "GET",
-		Path:      "/refunds/" + id,
-		ShopId:    y.ShopId,
-		SecretKey: y.SecretKey,
+		Method:     "GET",
+		Path:       "/refunds/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
 	}
 
 	bytes, err := r.SendRequest()
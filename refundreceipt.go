@@ -0,0 +1,192 @@
+package yandex
+
+import (
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReturnedItem selects a quantity of the item at Index in the original
+// receipt.
+type ReturnedItem struct {
+	Index    int
+	Quantity decimal.Decimal
+}
+
+// RefundReceiptBuilder derives refunds for returned goods from the receipt
+// sent with the payment. Refunded lists the items returned by earlier
+// refunds of the same payment.
+type RefundReceiptBuilder struct {
+	Payment  *Payment
+	Receipt  *Receipt
+	Refunded []*ReturnedItem
+}
+
+func NewRefundReceiptBuilder(p *Payment, receipt *Receipt) *RefundReceiptBuilder {
+	return &RefundReceiptBuilder{
+		Payment: p,
+		Receipt: receipt,
+	}
+}
+
+func (b *RefundReceiptBuilder) WithRefunded(items ...*ReturnedItem) *RefundReceiptBuilder {
+	b.Refunded = append(b.Refunded, items...)
+
+	return b
+}
+
+func (b *RefundReceiptBuilder) Build(returned ...*ReturnedItem) (*RefundRequest, error) {
+	if b.Payment == nil || b.Payment.Amount == nil {
+		return nil, newValidationError("payment_id", "Payment is required")
+	}
+
+	if b.Receipt == nil || len(b.Receipt.Items) == 0 {
+		return nil, newValidationError("receipt.items", "Original receipt items are required")
+	}
+
+	if len(returned) == 0 {
+		return nil, newValidationError("receipt.items", "At least one returned item is required")
+	}
+
+	totals := b.paidTotals()
+
+	refunded, err := b.quantities(b.Refunded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	returning, err := b.quantities(returned)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*Item{}
+
+	for i, it := range b.Receipt.Items {
+		q, ok := returning[i]
+
+		if !ok {
+			continue
+		}
+
+		prev := refunded[i]
+
+		if prev.Add(q).GreaterThan(it.Quantity) {
+			return nil, newValidationError("receipt.items.quantity", "Returned quantity of item "+strconv.Itoa(i)+" exceeds "+it.Quantity.Sub(prev).String()+" remaining after previous refunds")
+		}
+
+		share := func(qty decimal.Decimal) decimal.Decimal {
+			return totals[i].Mul(qty).Div(it.Quantity).Round(2)
+		}
+
+		items = append(items, splitItem(it, q, share(prev.Add(q)).Sub(share(prev)))...)
+	}
+
+	amount := &Amount{
+		Value:    itemsTotal(items),
+		Currency: b.Payment.Amount.Currency,
+	}
+
+	remaining := b.Payment.Amount.Value
+
+	if b.Payment.RefundedAmount != nil {
+		remaining = remaining.Sub(b.Payment.RefundedAmount.Value)
+	}
+
+	if amount.Value.GreaterThan(remaining) {
+		return nil, newValidationError("amount.value", "Refund amount "+amount.Value.StringFixed(2)+" exceeds "+remaining.StringFixed(2)+" remaining after previous refunds")
+	}
+
+	receipt := &Receipt{
+		Customer:                  b.Receipt.Customer,
+		Items:                     items,
+		TaxSystemCode:             b.Receipt.TaxSystemCode,
+		Phone:                     b.Receipt.Phone,
+		Email:                     b.Receipt.Email,
+		ReceiptIndustryDetails:    b.Receipt.ReceiptIndustryDetails,
+		ReceiptOperationalDetails: b.Receipt.ReceiptOperationalDetails,
+	}
+
+	if err := receipt.ValidateFor(amount); err != nil {
+		return nil, err
+	}
+
+	return &RefundRequest{
+		PaymentId: b.Payment.Id,
+		Amount:    amount,
+		Receipt:   receipt,
+	}, nil
+}
+
+// paidTotals returns the line totals the customer actually paid. When the
+// payment is less than the receipt total, the order discount is spread over
+// the lines proportionally to their totals.
+func (b *RefundReceiptBuilder) paidTotals() []decimal.Decimal {
+	totals := make([]decimal.Decimal, len(b.Receipt.Items))
+
+	for i, it := range b.Receipt.Items {
+		totals[i] = it.Total()
+	}
+
+	if b.Payment.Amount.Value.LessThan(itemsTotal(b.Receipt.Items)) {
+		return allocate(b.Payment.Amount.Value, totals)
+	}
+
+	return totals
+}
+
+func (b *RefundReceiptBuilder) quantities(selected []*ReturnedItem) (map[int]decimal.Decimal, error) {
+	res := map[int]decimal.Decimal{}
+
+	for _, s := range selected {
+		if s.Index < 0 || s.Index >= len(b.Receipt.Items) {
+			return nil, newValidationError("receipt.items", "Returned item "+strconv.Itoa(s.Index)+" isn't in the original receipt")
+		}
+
+		if !s.Quantity.IsPositive() {
+			return nil, newValidationError("receipt.items.quantity", "Returned quantity must be greater than zero")
+		}
+
+		res[s.Index] = res[s.Index].Add(s.Quantity)
+	}
+
+	return res, nil
+}
+
+// splitItem copies the original item for the returned quantity. A price
+// with kopeck fractions isn't allowed, so when the amount doesn't divide
+// evenly the last unit is moved to a separate line carrying the remainder.
+func splitItem(original *Item, quantity, amount decimal.Decimal) []*Item {
+	line := func(q, price decimal.Decimal) *Item {
+		it := *original
+		it.Quantity = q
+		it.Amount = &Amount{
+			Value:    price,
+			Currency: original.Amount.Currency,
+		}
+
+		return &it
+	}
+
+	price := amount.Div(quantity).Round(2)
+
+	if price.Mul(quantity).Round(2).Equal(amount) {
+		return []*Item{line(quantity, price)}
+	}
+
+	one := decimal.NewFromInt(1)
+
+	if !quantity.Equal(quantity.Truncate(0)) || quantity.Equal(one) {
+		return []*Item{line(quantity, price)}
+	}
+
+	price = amount.Div(quantity).Truncate(2)
+	rest := quantity.Sub(one)
+
+	return []*Item{
+		line(rest, price),
+		line(one, amount.Sub(price.Mul(rest))),
+	}
+}
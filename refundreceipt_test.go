@@ -0,0 +1,181 @@
+package yandex
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func returned(index int, quantity int64) *ReturnedItem {
+	return &ReturnedItem{
+		Index:    index,
+		Quantity: decimal.NewFromInt(quantity),
+	}
+}
+
+type refundLine struct {
+	quantity string
+	price    string
+}
+
+func TestRefundReceiptBuilder(t *testing.T) {
+	order := NewReceipt("buyer@example.com", "").
+		AddItem("Tea", decimal.NewFromInt(3), rub("100"), VATCode20).
+		AddItem("Cup", decimal.NewFromInt(1), rub("200"), VATCode20)
+
+	tests := []struct {
+		name     string
+		paid     string
+		refunded string
+		previous []*ReturnedItem
+		returned []*ReturnedItem
+		amount   string
+		lines    []refundLine
+	}{
+		{
+			name:     "without discount",
+			paid:     "500",
+			returned: []*ReturnedItem{returned(0, 2)},
+			amount:   "200",
+			lines:    []refundLine{{"2", "100"}},
+		},
+		{
+			name:     "discount spread over lines",
+			paid:     "450",
+			returned: []*ReturnedItem{returned(0, 2)},
+			amount:   "180",
+			lines:    []refundLine{{"2", "90"}},
+		},
+		{
+			name:     "rest after earlier refund",
+			paid:     "450",
+			refunded: "180",
+			previous: []*ReturnedItem{returned(0, 2)},
+			returned: []*ReturnedItem{returned(0, 1), returned(1, 1)},
+			amount:   "270",
+			lines:    []refundLine{{"1", "90"}, {"1", "180"}},
+		},
+		{
+			name:     "uneven discount split into lines",
+			paid:     "449.99",
+			returned: []*ReturnedItem{returned(0, 3)},
+			amount:   "269.99",
+			lines:    []refundLine{{"2", "89.99"}, {"1", "90.01"}},
+		},
+		{
+			name:     "selections of one item are merged",
+			paid:     "500",
+			returned: []*ReturnedItem{returned(0, 1), returned(0, 1)},
+			amount:   "200",
+			lines:    []refundLine{{"2", "100"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Payment{Id: "p", Status: PaymentStatusSucceeded, Amount: rub(tt.paid)}
+
+			if len(tt.refunded) > 0 {
+				p.RefundedAmount = rub(tt.refunded)
+			}
+
+			req, err := NewRefundReceiptBuilder(p, order).WithRefunded(tt.previous...).Build(tt.returned...)
+
+			if err != nil {
+				t.Fatalf("Build() = %v", err)
+			}
+
+			if !req.Amount.Value.Equal(decimal.RequireFromString(tt.amount)) {
+				t.Errorf("amount = %s, want %s", req.Amount.Value, tt.amount)
+			}
+
+			if !req.Receipt.Total().Equal(req.Amount.Value) {
+				t.Errorf("receipt total %s doesn't match amount %s", req.Receipt.Total(), req.Amount.Value)
+			}
+
+			if len(req.Receipt.Items) != len(tt.lines) {
+				t.Fatalf("got %d lines, want %d", len(req.Receipt.Items), len(tt.lines))
+			}
+
+			for i, l := range tt.lines {
+				it := req.Receipt.Items[i]
+
+				if !it.Quantity.Equal(decimal.RequireFromString(l.quantity)) || !it.Amount.Value.Equal(decimal.RequireFromString(l.price)) {
+					t.Errorf("line %d = %s x %s, want %s x %s", i, it.Quantity, it.Amount.Value, l.quantity, l.price)
+				}
+			}
+		})
+	}
+}
+
+func TestRefundReceiptBuilderTotalsExactly(t *testing.T) {
+	order := NewReceipt("buyer@example.com", "").
+		AddItem("Tea", decimal.NewFromInt(3), rub("33.33"), VATCode20).
+		AddItem("Cup", decimal.NewFromInt(7), rub("14.29"), VATCode10).
+		AddItem("Spoon", decimal.NewFromInt(1), rub("9.99"), VATCodeNoVAT)
+
+	p := &Payment{Id: "p", Status: PaymentStatusSucceeded, Amount: rub("180")}
+	b := NewRefundReceiptBuilder(p, order)
+	total := decimal.Zero
+
+	for _, step := range [][]*ReturnedItem{
+		{returned(0, 1), returned(1, 3)},
+		{returned(1, 2), returned(2, 1)},
+		{returned(0, 2), returned(1, 2)},
+	} {
+		req, err := b.Build(step...)
+
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+
+		if !req.Receipt.Total().Equal(req.Amount.Value) {
+			t.Errorf("receipt total %s doesn't match amount %s", req.Receipt.Total(), req.Amount.Value)
+		}
+
+		total = total.Add(req.Amount.Value)
+		p.RefundedAmount = &Amount{Value: total, Currency: "RUB"}
+		b.WithRefunded(step...)
+	}
+
+	if !total.Equal(p.Amount.Value) {
+		t.Errorf("refunds total %s, want %s", total, p.Amount.Value)
+	}
+
+	if _, err := b.Build(returned(2, 1)); err == nil {
+		t.Error("Build() after full refund succeeded")
+	}
+}
+
+func TestRefundReceiptBuilderErrors(t *testing.T) {
+	order := NewReceipt("buyer@example.com", "").
+		AddItem("Tea", decimal.NewFromInt(3), rub("100"), VATCode20)
+
+	tests := []struct {
+		name     string
+		refunded string
+		previous []*ReturnedItem
+		returned []*ReturnedItem
+	}{
+		{"nothing returned", "", nil, nil},
+		{"unknown item", "", nil, []*ReturnedItem{returned(1, 1)}},
+		{"zero quantity", "", nil, []*ReturnedItem{returned(0, 0)}},
+		{"more than bought", "", nil, []*ReturnedItem{returned(0, 4)}},
+		{"more than remains", "200", []*ReturnedItem{returned(0, 2)}, []*ReturnedItem{returned(0, 2)}},
+		{"amount over refunded", "250", nil, []*ReturnedItem{returned(0, 1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Payment{Id: "p", Status: PaymentStatusSucceeded, Amount: rub("300")}
+
+			if len(tt.refunded) > 0 {
+				p.RefundedAmount = rub(tt.refunded)
+			}
+
+			if _, err := NewRefundReceiptBuilder(p, order).WithRefunded(tt.previous...).Build(tt.returned...); err == nil {
+				t.Error("Build() succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package yandex
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+)
+
+const (
+	SavedPaymentMethodPending  string = "pending"
+	SavedPaymentMethodActive   string = "active"
+	SavedPaymentMethodInactive string = "inactive"
+)
+
+var ErrPermissionRevoked = errors.New("yandex: saved payment method was revoked by the customer")
+
+type Holder struct {
+	AccountId string `json:"account_id"`
+	GatewayId string `json:"gateway_id,omitempty"`
+}
+
+type SavedPaymentMethod struct {
+	Type         string        `json:"type"`
+	Id           string        `json:"id"`
+	Saved        bool          `json:"saved"`
+	Status       string        `json:"status"`
+	Title        string        `json:"title,omitempty"`
+	Card         *Card         `json:"card,omitempty"`
+	Holder       *Holder       `json:"holder,omitempty"`
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+type SavePaymentMethodRequest struct {
+	Type         string        `json:"type"`
+	Card         *Card         `json:"card,omitempty"`
+	Holder       *Holder       `json:"holder,omitempty"`
+	ClientIp     string        `json:"client_ip,omitempty"`
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+func (y *Yandex) SavePaymentMethod(idempKey string, req *SavePaymentMethodRequest) (*SavedPaymentMethod, error) {
+	if req.Confirmation != nil {
+		if err := req.Confirmation.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/payment_methods",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.ShopId,
+		SecretKey:      y.SecretKey,
+		IdempotenceKey: idempKey,
+		Body:           req,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SavedPaymentMethod{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetSavedPaymentMethod(id string) (*SavedPaymentMethod, error) {
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/payment_methods/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.ShopId,
+		SecretKey:  y.SecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SavedPaymentMethod{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ChargeSavedPaymentMethod creates a one-stage payment without customer
+// interaction. If the customer revoked the saved method, the canceled
+// payment is returned together with ErrPermissionRevoked.
+func (y *Yandex) ChargeSavedPaymentMethod(idempKey, methodId string, req *PaymentRequest) (*Payment, error) {
+	charge := *req
+	charge.PaymentMethodId = methodId
+	charge.PaymentToken = ""
+	charge.PaymentMethodData = nil
+	charge.methodErr = nil
+	charge.Confirmation = nil
+	charge.SavePaymentMethod = false
+	charge.Capture = true
+
+	res, err := y.CreatePayment(idempKey, &charge)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.IsPermissionRevoked() {
+		return res, ErrPermissionRevoked
+	}
+
+	return res, nil
+}
+
+func (r *PaymentRequest) WithSavePaymentMethod() *PaymentRequest {
+	r.SavePaymentMethod = true
+
+	return r
+}
+
+func (m *SavedPaymentMethod) IsActive() bool {
+	return m.Status == SavedPaymentMethodActive
+}
+
+func (p *Payment) IsPermissionRevoked() bool {
+	return p.Status == PaymentStatusCanceled && p.CancellationDetails != nil && p.CancellationDetails.Reason == CancellationReasonPermissionRevoked
+}
@@ -0,0 +1,189 @@
+package yandex
+
+import (
+	"encoding/json"
+	"log"
+	"regexp"
+)
+
+const (
+	SelfEmployedStatusPending      string = "pending"
+	SelfEmployedStatusInProgress   string = "in_progress"
+	SelfEmployedStatusConfirmed    string = "confirmed"
+	SelfEmployedStatusCanceled     string = "canceled"
+	SelfEmployedStatusUnregistered string = "unregistered"
+)
+
+var itnPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+type SelfEmployedConfirmation struct {
+	Type            string `json:"type"`
+	ConfirmationUrl string `json:"confirmation_url,omitempty"`
+}
+
+type SelfEmployed struct {
+	Id           string                    `json:"id"`
+	Status       string                    `json:"status"`
+	CreatedAt    string                    `json:"created_at"`
+	ITN          string                    `json:"itn,omitempty"`
+	Phone        string                    `json:"phone,omitempty"`
+	Confirmation *SelfEmployedConfirmation `json:"confirmation,omitempty"`
+	Test         bool                      `json:"test"`
+}
+
+type SelfEmployedRequest struct {
+	ITN          string                    `json:"itn,omitempty"`
+	Phone        string                    `json:"phone,omitempty"`
+	Description  string                    `json:"description,omitempty"`
+	Confirmation *SelfEmployedConfirmation `json:"confirmation,omitempty"`
+}
+
+type PayoutSelfEmployed struct {
+	Id string `json:"id"`
+}
+
+type PayoutReceiptData struct {
+	ServiceName string  `json:"service_name"`
+	Amount      *Amount `json:"amount,omitempty"`
+}
+
+func (y *Yandex) CreateSelfEmployed(idempKey string, req *SelfEmployedRequest) (*SelfEmployed, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &HttpRequest{
+		Method:         "POST",
+		Path:           "/self_employed",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
+		ShopId:         y.AgentId,
+		SecretKey:      y.AgentSecretKey,
+		IdempotenceKey: idempKey,
+		Body:           req,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SelfEmployed{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (y *Yandex) GetSelfEmployed(id string) (*SelfEmployed, error) {
+	if len(y.AgentId) == 0 || len(y.AgentSecretKey) == 0 {
+		return nil, ErrNoAgentCredentials
+	}
+
+	r := &HttpRequest{
+		Method:     "GET",
+		Path:       "/self_employed/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
+		ShopId:     y.AgentId,
+		SecretKey:  y.AgentSecretKey,
+	}
+
+	bytes, err := r.SendRequest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SelfEmployed{}
+
+	if err := json.Unmarshal(bytes, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *SelfEmployedRequest) WithRedirectConfirmation() *SelfEmployedRequest {
+	r.Confirmation = &SelfEmployedConfirmation{
+		Type: ConfirmationRedirect,
+	}
+
+	return r
+}
+
+func (r *SelfEmployedRequest) Validate() error {
+	if len(r.ITN) == 0 && len(r.Phone) == 0 {
+		return newValidationError("itn", "Either itn or phone must be specified")
+	}
+
+	if len(r.ITN) > 0 && !itnPattern.MatchString(r.ITN) {
+		return newValidationError("itn", "ITN of self-employed must contain 12 digits")
+	}
+
+	if len(r.Phone) > 0 && !phonePattern.MatchString(r.Phone) {
+		return newValidationError("phone", "Phone must be in ITU-T E.164 format without plus sign")
+	}
+
+	if r.Confirmation != nil && r.Confirmation.Type != ConfirmationRedirect {
+		return newValidationError("confirmation.type", "Only redirect confirmation is supported for self-employed")
+	}
+
+	return nil
+}
+
+// CanReceivePayouts reports whether the self-employed person granted the
+// rights to register receipts on their behalf.
+func (s *SelfEmployed) CanReceivePayouts() bool {
+	return s.Status == SelfEmployedStatusConfirmed
+}
+
+func (s *SelfEmployed) IsFinal() bool {
+	switch s.Status {
+	case SelfEmployedStatusConfirmed, SelfEmployedStatusCanceled, SelfEmployedStatusUnregistered:
+		return true
+	}
+
+	return false
+}
+
+func (r *PayoutRequest) WithSelfEmployed(id, serviceName string) *PayoutRequest {
+	r.SelfEmployed = &PayoutSelfEmployed{
+		Id: id,
+	}
+
+	r.ReceiptData = &PayoutReceiptData{
+		ServiceName: serviceName,
+	}
+
+	return r
+}
+
+func (d *PayoutReceiptData) Validate(payout *Amount) error {
+	if len(d.ServiceName) == 0 || len([]rune(d.ServiceName)) > 50 {
+		return newValidationError("receipt_data.service_name", "Service name must contain 1 to 50 characters")
+	}
+
+	if d.Amount == nil {
+		return nil
+	}
+
+	if !d.Amount.Value.IsPositive() || !d.Amount.Value.Round(2).Equal(d.Amount.Value) {
+		return newValidationError("receipt_data.amount.value", "Receipt amount must be greater than zero with at most two decimal places")
+	}
+
+	if payout != nil && d.Amount.Currency != payout.Currency {
+		return newValidationError("receipt_data.amount.currency", "Receipt amount currency must match payout currency")
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package yandex
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TransferStatusPending           string = "pending"
+	TransferStatusWaitingForCapture string = "waiting_for_capture"
+	TransferStatusSucceeded         string = "succeeded"
+	TransferStatusCanceled          string = "canceled"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+type CommissionPolicy struct {
+	Percent decimal.Decimal
+	Fixed   decimal.Decimal
+}
+
+type Share struct {
+	AccountId   string
+	Weight      decimal.Decimal
+	Description string
+	Metadata    map[string]interface{}
+}
+
+type Split struct {
+	Amount     *Amount
+	Commission *CommissionPolicy
+	Shares     []*Share
+}
+
+func NewSplit(amount *Amount) *Split {
+	return &Split{
+		Amount: amount,
+	}
+}
+
+func (s *Split) AddSeller(accountId string, weight decimal.Decimal, description string, metadata map[string]interface{}) *Split {
+	s.Shares = append(s.Shares, &Share{
+		AccountId:   accountId,
+		Weight:      weight,
+		Description: description,
+		Metadata:    metadata,
+	})
+
+	return s
+}
+
+func (s *Split) WithCommission(percent, fixed decimal.Decimal) *Split {
+	s.Commission = &CommissionPolicy{
+		Percent: percent,
+		Fixed:   fixed,
+	}
+
+	return s
+}
+
+func (s *Split) Transfers() ([]*Transfer, error) {
+	if s.Amount == nil || !s.Amount.Value.IsPositive() {
+		return nil, newValidationError("transfers", "Split amount must be greater than zero")
+	}
+
+	if len(s.Shares) == 0 {
+		return nil, newValidationError("transfers", "Split has no sellers")
+	}
+
+	weights := make([]decimal.Decimal, len(s.Shares))
+
+	for i, share := range s.Shares {
+		if len(share.AccountId) == 0 {
+			return nil, newValidationError("transfers.account_id", "Seller account id is required")
+		}
+
+		if !share.Weight.IsPositive() {
+			return nil, newValidationError("transfers.amount", "Seller share must be greater than zero")
+		}
+
+		weights[i] = share.Weight
+	}
+
+	amounts := allocate(s.Amount.Value, weights)
+	res := make([]*Transfer, len(s.Shares))
+
+	for i, share := range s.Shares {
+		t := &Transfer{
+			AccountId: share.AccountId,
+			Amount: &Amount{
+				Value:    amounts[i],
+				Currency: s.Amount.Currency,
+			},
+			Description: share.Description,
+			Metadata:    share.Metadata,
+		}
+
+		if s.Commission != nil {
+			fee := s.Commission.Fee(amounts[i])
+
+			if fee.GreaterThan(amounts[i]) {
+				return nil, newValidationError("transfers.platform_fee_amount", "Platform fee exceeds transfer amount for "+share.AccountId)
+			}
+
+			t.PlatformFeeAmount = &Amount{
+				Value:    fee,
+				Currency: s.Amount.Currency,
+			}
+		}
+
+		res[i] = t
+	}
+
+	return res, nil
+}
+
+func (c *CommissionPolicy) Fee(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(c.Percent).Div(hundred).Add(c.Fixed).Round(2)
+}
+
+// RefundSources spreads a refund over the payment transfers in proportion
+// to their amounts, returning platform fees in the same proportion.
+func RefundSources(transfers []*Transfer, refund *Amount) ([]*Source, error) {
+	if refund == nil || !refund.Value.IsPositive() {
+		return nil, newValidationError("amount", "Refund amount must be greater than zero")
+	}
+
+	total := decimal.Zero
+	weights := make([]decimal.Decimal, len(transfers))
+	fees := make([]decimal.Decimal, len(transfers))
+	feeTotal := decimal.Zero
+
+	for i, t := range transfers {
+		weights[i] = t.Amount.Value
+		total = total.Add(t.Amount.Value)
+
+		if t.PlatformFeeAmount != nil {
+			fees[i] = t.PlatformFeeAmount.Value
+			feeTotal = feeTotal.Add(t.PlatformFeeAmount.Value)
+		}
+	}
+
+	if refund.Value.GreaterThan(total) {
+		return nil, newValidationError("amount", "Refund amount exceeds sum of transfers")
+	}
+
+	amounts := allocate(refund.Value, weights)
+	res := []*Source{}
+
+	var feeRefunds []decimal.Decimal
+
+	if feeTotal.IsPositive() {
+		feeRefunds = allocate(feeTotal.Mul(refund.Value).Div(total).Round(2), fees)
+	}
+
+	for i, t := range transfers {
+		// Small refunds over many transfers leave some of them without a
+		// kopeck, the API rejects sources with zero amount.
+		if !amounts[i].IsPositive() {
+			continue
+		}
+
+		src := &Source{
+			AccountId: t.AccountId,
+			Amount: &Amount{
+				Value:    amounts[i],
+				Currency: refund.Currency,
+			},
+		}
+
+		if feeRefunds != nil && feeRefunds[i].IsPositive() {
+			src.PlatformFeeAmount = &Amount{
+				Value:    feeRefunds[i],
+				Currency: refund.Currency,
+			}
+		}
+
+		res = append(res, src)
+	}
+
+	return res, nil
+}
+
+func ValidateTransfers(amount *Amount, transfers []*Transfer) error {
+	total := decimal.Zero
+
+	for _, t := range transfers {
+		if len(t.AccountId) == 0 {
+			return newValidationError("transfers.account_id", "Transfer account id is required")
+		}
+
+		if t.Amount == nil || !t.Amount.Value.IsPositive() {
+			return newValidationError("transfers.amount", "Transfer amount must be greater than zero")
+		}
+
+		if t.Amount.Currency != amount.Currency {
+			return newValidationError("transfers.amount.currency", "Transfer currency doesn't match payment currency")
+		}
+
+		if t.PlatformFeeAmount != nil && t.PlatformFeeAmount.Value.GreaterThan(t.Amount.Value) {
+			return newValidationError("transfers.platform_fee_amount", "Platform fee exceeds transfer amount")
+		}
+
+		total = total.Add(t.Amount.Value)
+	}
+
+	if !total.Equal(amount.Value) {
+		return newValidationError("transfers", fmt.Sprintf("Sum of transfers %s doesn't match amount %s", total.StringFixed(2), amount.Value.StringFixed(2)))
+	}
+
+	return nil
+}
+
+func ValidateSources(transfers []*Transfer, refund *Amount, sources []*Source) error {
+	if refund == nil {
+		return newValidationError("amount", "Refund amount is required")
+	}
+
+	byAccount := map[string]*Transfer{}
+
+	for _, t := range transfers {
+		byAccount[t.AccountId] = t
+	}
+
+	total := decimal.Zero
+
+	for _, src := range sources {
+		t, ok := byAccount[src.AccountId]
+
+		if !ok {
+			return newValidationError("sources.account_id", "Source account "+src.AccountId+" isn't a payment transfer")
+		}
+
+		if src.Amount == nil || !src.Amount.Value.IsPositive() {
+			return newValidationError("sources.amount", "Source amount must be greater than zero for "+src.AccountId)
+		}
+
+		if src.Amount.Value.GreaterThan(t.Amount.Value) {
+			return newValidationError("sources.amount", "Source amount exceeds transfer amount for "+src.AccountId)
+		}
+
+		total = total.Add(src.Amount.Value)
+	}
+
+	if !total.Equal(refund.Value) {
+		return newValidationError("sources", "Sum of sources doesn't match refund amount")
+	}
+
+	return nil
+}
+
+// ReconcileTransfers copies transfer statuses from the payment response to
+// the transfers that were sent, failing if the API changed the split.
+func ReconcileTransfers(sent []*Transfer, p *Payment) error {
+	received := map[string]*Transfer{}
+
+	for _, t := range p.Transfers {
+		received[t.AccountId] = t
+	}
+
+	for _, t := range sent {
+		r, ok := received[t.AccountId]
+
+		if !ok {
+			return fmt.Errorf("yandex: transfer to %s is missing in payment %s", t.AccountId, p.Id)
+		}
+
+		if r.Amount == nil || !r.Amount.Value.Equal(t.Amount.Value) {
+			return fmt.Errorf("yandex: transfer to %s in payment %s has different amount", t.AccountId, p.Id)
+		}
+
+		t.Status = r.Status
+	}
+
+	return nil
+}
+
+func (r *PaymentRequest) WithTransfers(transfers []*Transfer) *PaymentRequest {
+	r.Transfers = transfers
+
+	return r
+}
+
+func allocate(total decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	sum := decimal.Zero
+
+	for _, w := range weights {
+		sum = sum.Add(w)
+	}
+
+	res := make([]decimal.Decimal, len(weights))
+
+	if !sum.IsPositive() {
+		return res
+	}
+
+	kopecks := total.Mul(hundred).Round(0).IntPart()
+	remainders := make([]decimal.Decimal, len(weights))
+	left := kopecks
+
+	for i, w := range weights {
+		exact := decimal.NewFromInt(kopecks).Mul(w).Div(sum)
+		whole := exact.Floor()
+		res[i] = whole
+		remainders[i] = exact.Sub(whole)
+		left -= whole.IntPart()
+	}
+
+	for ; left > 0; left-- {
+		best := 0
+
+		for i := range remainders {
+			if remainders[i].GreaterThan(remainders[best]) {
+				best = i
+			}
+		}
+
+		res[best] = res[best].Add(decimal.NewFromInt(1))
+		remainders[best] = decimal.NewFromInt(-1)
+	}
+
+	for i := range res {
+		res[i] = res[i].Div(hundred)
+	}
+
+	return res
+}
@@ -0,0 +1,129 @@
+package yandex
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func rub(v string) *Amount {
+	return &Amount{
+		Value:    decimal.RequireFromString(v),
+		Currency: "RUB",
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   string
+		weights []string
+		want    []string
+	}{
+		{"even", "90", []string{"1", "1", "1"}, []string{"30", "30", "30"}},
+		{"kopeck remainder", "100", []string{"1", "1", "1"}, []string{"33.34", "33.33", "33.33"}},
+		{"largest remainder", "10", []string{"1", "2", "4"}, []string{"1.43", "2.86", "5.71"}},
+		{"fewer kopecks than weights", "0.02", []string{"1", "1", "1"}, []string{"0.01", "0.01", "0"}},
+		{"zero weights", "10", []string{"0", "0"}, []string{"0", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights := make([]decimal.Decimal, len(tt.weights))
+
+			for i, w := range tt.weights {
+				weights[i] = decimal.RequireFromString(w)
+			}
+
+			got := allocate(decimal.RequireFromString(tt.total), weights)
+
+			for i, want := range tt.want {
+				if !got[i].Equal(decimal.RequireFromString(want)) {
+					t.Errorf("share %d = %s, want %s", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRefundSources(t *testing.T) {
+	transfers := []*Transfer{
+		{AccountId: "1", Amount: rub("100"), PlatformFeeAmount: rub("10")},
+		{AccountId: "2", Amount: rub("100"), PlatformFeeAmount: rub("10")},
+		{AccountId: "3", Amount: rub("100")},
+	}
+
+	tests := []struct {
+		name    string
+		refund  *Amount
+		want    map[string]string
+		wantErr bool
+	}{
+		{"full refund", rub("300"), map[string]string{"1": "100", "2": "100", "3": "100"}, false},
+		{"kopeck refund drops zero source", rub("0.02"), map[string]string{"1": "0.01", "2": "0.01"}, false},
+		{"uneven refund", rub("100"), map[string]string{"1": "33.34", "2": "33.33", "3": "33.33"}, false},
+		{"exceeds transfers", rub("300.01"), nil, true},
+		{"zero refund", rub("0"), nil, true},
+		{"nil refund", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RefundSources(transfers, tt.refund)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d sources, want %d", len(got), len(tt.want))
+			}
+
+			for _, src := range got {
+				if !src.Amount.Value.Equal(decimal.RequireFromString(tt.want[src.AccountId])) {
+					t.Errorf("source %s = %s, want %s", src.AccountId, src.Amount.Value, tt.want[src.AccountId])
+				}
+			}
+
+			if err := ValidateSources(transfers, tt.refund, got); err != nil {
+				t.Errorf("ValidateSources() = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSources(t *testing.T) {
+	transfers := []*Transfer{
+		{AccountId: "1", Amount: rub("100")},
+		{AccountId: "2", Amount: rub("50")},
+	}
+
+	tests := []struct {
+		name    string
+		refund  *Amount
+		sources []*Source
+		wantErr bool
+	}{
+		{"valid", rub("60"), []*Source{{AccountId: "1", Amount: rub("30")}, {AccountId: "2", Amount: rub("30")}}, false},
+		{"nil refund", nil, []*Source{{AccountId: "1", Amount: rub("30")}}, true},
+		{"zero source", rub("30"), []*Source{{AccountId: "1", Amount: rub("30")}, {AccountId: "2", Amount: rub("0")}}, true},
+		{"negative source", rub("20"), []*Source{{AccountId: "1", Amount: rub("30")}, {AccountId: "2", Amount: rub("-10")}}, true},
+		{"exceeds transfer", rub("60"), []*Source{{AccountId: "2", Amount: rub("60")}}, true},
+		{"unknown account", rub("10"), []*Source{{AccountId: "3", Amount: rub("10")}}, true},
+		{"sum mismatch", rub("50"), []*Source{{AccountId: "1", Amount: rub("30")}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSources(transfers, tt.refund, tt.sources)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ func (y *Yandex) GetStoreInfo() (*Store, error) {
 	r := &HttpRequest{
 		Method:     "GET",
 		Path:       "/me",
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
 		OAuthToken: y.OAuthToken,
 	}
 
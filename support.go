@@ -0,0 +1,221 @@
+package yandex
+
+type PaymentMethodSupport struct {
+	Confirmations     []string
+	NoConfirmation    bool
+	TwoStage          bool
+	SavePaymentMethod bool
+}
+
+var (
+	redirectConfirmations = []string{ConfirmationRedirect, ConfirmationEmbedded, ConfirmationMobileApplication}
+	paymentMethodSupport  = map[string]*PaymentMethodSupport{
+		PaymentMethodBankCard: {
+			Confirmations:     redirectConfirmations,
+			NoConfirmation:    true,
+			TwoStage:          true,
+			SavePaymentMethod: true,
+		},
+		PaymentMethodSberbank: {
+			Confirmations:     []string{ConfirmationRedirect, ConfirmationEmbedded, ConfirmationExternal, ConfirmationMobileApplication, ConfirmationQR},
+			TwoStage:          true,
+			SavePaymentMethod: true,
+		},
+		PaymentMethodTinkoffBank: {
+			Confirmations:     redirectConfirmations,
+			TwoStage:          true,
+			SavePaymentMethod: true,
+		},
+		PaymentMethodYooMoney: {
+			Confirmations:     redirectConfirmations,
+			TwoStage:          true,
+			SavePaymentMethod: true,
+		},
+		PaymentMethodSBP: {
+			Confirmations:     []string{ConfirmationRedirect, ConfirmationEmbedded, ConfirmationQR, ConfirmationMobileApplication},
+			SavePaymentMethod: true,
+		},
+		PaymentMethodMobileBalance: {
+			Confirmations: []string{ConfirmationExternal},
+			TwoStage:      true,
+		},
+		PaymentMethodCash: {
+			Confirmations: []string{ConfirmationExternal},
+		},
+		PaymentMethodB2BSberbank: {
+			Confirmations: []string{ConfirmationRedirect},
+		},
+		PaymentMethodInstallments: {
+			Confirmations: []string{ConfirmationRedirect},
+		},
+		PaymentMethodSberLoan: {
+			Confirmations: []string{ConfirmationRedirect, ConfirmationEmbedded},
+		},
+		PaymentMethodSberBNPL: {
+			Confirmations: []string{ConfirmationRedirect, ConfirmationEmbedded},
+		},
+		PaymentMethodElectronicCertificate: {
+			Confirmations: []string{ConfirmationRedirect, ConfirmationEmbedded},
+		},
+		PaymentMethodApplePay: {
+			Confirmations:  []string{ConfirmationRedirect},
+			NoConfirmation: true,
+			TwoStage:       true,
+		},
+		PaymentMethodGooglePay: {
+			Confirmations:  []string{ConfirmationRedirect},
+			NoConfirmation: true,
+			TwoStage:       true,
+		},
+		PaymentMethodAlfaBank: {
+			Confirmations: []string{ConfirmationExternal, ConfirmationRedirect},
+			TwoStage:      true,
+		},
+		PaymentMethodYandexMoney: {
+			Confirmations:     redirectConfirmations,
+			TwoStage:          true,
+			SavePaymentMethod: true,
+		},
+		PaymentMethodQiwi: {
+			Confirmations: []string{ConfirmationRedirect},
+		},
+		PaymentMethodWeChat: {
+			Confirmations: []string{ConfirmationQR},
+		},
+		PaymentMethodWebmoney: {
+			Confirmations: []string{ConfirmationRedirect},
+			TwoStage:      true,
+		},
+	}
+)
+
+func GetPaymentMethodSupport(kind string) (*PaymentMethodSupport, bool) {
+	paymentMethodsMu.RLock()
+	defer paymentMethodsMu.RUnlock()
+
+	s, ok := paymentMethodSupport[kind]
+
+	return s, ok
+}
+
+func (s *PaymentMethodSupport) SupportsConfirmation(kind string) bool {
+	for _, c := range s.Confirmations {
+		if c == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Store) SupportsPaymentMethod(kind string) bool {
+	for _, m := range s.PaymentMethods {
+		if m == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Store) UnknownPaymentMethods() []string {
+	res := []string{}
+
+	for _, m := range s.PaymentMethods {
+		if _, ok := GetPaymentMethodSupport(m); !ok {
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// Validate checks the request against the compatibility table offline.
+// CreatePayment doesn't call it, since the table can lag behind the API.
+func (r *PaymentRequest) Validate() error {
+	if r.Amount == nil || !r.Amount.Value.IsPositive() {
+		return newValidationError("amount.value", "Amount must be greater than zero")
+	}
+
+	if !r.Amount.Value.Round(2).Equal(r.Amount.Value) {
+		return newValidationError("amount.value", "Amount must have at most two decimal places")
+	}
+
+	if len(r.Amount.Currency) != 3 {
+		return newValidationError("amount.currency", "Currency must be ISO-4217 code")
+	}
+
+	sources := 0
+
+	for _, set := range []bool{len(r.PaymentToken) > 0, len(r.PaymentMethodId) > 0, r.PaymentMethodData != nil} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return newValidationError("payment_method_data", "Only one of payment_token, payment_method_id and payment_method_data can be specified")
+	}
+
+	if r.Confirmation != nil {
+		if err := r.Confirmation.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Receipt != nil {
+		if err := r.Receipt.ValidateFor(r.Amount); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Transfers) > 0 {
+		if err := ValidateTransfers(r.Amount, r.Transfers); err != nil {
+			return err
+		}
+	}
+
+	if r.PaymentMethodData == nil {
+		return nil
+	}
+
+	if err := r.PaymentMethodData.Validate(); err != nil {
+		return err
+	}
+
+	support, ok := GetPaymentMethodSupport(r.PaymentMethodData.Type)
+
+	if !ok {
+		return nil
+	}
+
+	if r.Confirmation == nil && !support.NoConfirmation {
+		return newValidationError("confirmation", "Confirmation is required for "+r.PaymentMethodData.Type+" payment method")
+	}
+
+	if r.Confirmation != nil && !support.SupportsConfirmation(r.Confirmation.Type) {
+		return newValidationError("confirmation.type", "Payment method "+r.PaymentMethodData.Type+" doesn't support "+r.Confirmation.Type+" confirmation")
+	}
+
+	if !r.Capture && !support.TwoStage {
+		return newValidationError("capture", "Payment method "+r.PaymentMethodData.Type+" doesn't support two-stage payments, capture must be true")
+	}
+
+	if r.SavePaymentMethod && !support.SavePaymentMethod {
+		return newValidationError("save_payment_method", "Payment method "+r.PaymentMethodData.Type+" can't be saved")
+	}
+
+	return nil
+}
+
+func (r *PaymentRequest) ValidateForStore(store *Store) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if r.PaymentMethodData != nil && !store.SupportsPaymentMethod(r.PaymentMethodData.Type) {
+		return newValidationError("payment_method_data.type", "Payment method "+r.PaymentMethodData.Type+" isn't enabled for the store")
+	}
+
+	return nil
+}
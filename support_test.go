@@ -0,0 +1,100 @@
+package yandex
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type replyDoer struct {
+	body string
+	sent []byte
+}
+
+func (d *replyDoer) Do(req *fasthttp.Request, res *fasthttp.Response) error {
+	d.sent = append([]byte(nil), req.Body()...)
+
+	res.SetStatusCode(200)
+	res.SetBodyString(d.body)
+
+	return nil
+}
+
+func TestPaymentRequestValidate(t *testing.T) {
+	redirect := NewRedirectConfirmation("https://example.com")
+
+	tests := []struct {
+		name  string
+		req   *PaymentRequest
+		param string
+	}{
+		{"card without confirmation", (&PaymentRequest{Amount: rub("100"), Capture: true}).WithBankCard(testCard("5555555555554444")), ""},
+		{"two-stage card", (&PaymentRequest{Amount: rub("100"), Confirmation: redirect}).WithBankCard(nil), ""},
+		{"saved sbp", (&PaymentRequest{Amount: rub("100"), Confirmation: NewQRConfirmation(), Capture: true, SavePaymentMethod: true}).WithSBP(), ""},
+		{"zero amount", &PaymentRequest{Amount: rub("0")}, "amount.value"},
+		{"fractional kopecks", &PaymentRequest{Amount: rub("1.001")}, "amount.value"},
+		{"currency", &PaymentRequest{Amount: &Amount{Value: rub("1").Value, Currency: "RUBL"}}, "amount.currency"},
+		{"two sources", (&PaymentRequest{Amount: rub("100"), PaymentToken: "token"}).WithSBP(), "payment_method_data"},
+		{"invalid confirmation", (&PaymentRequest{Amount: rub("100"), Confirmation: NewRedirectConfirmation("")}).WithSBP(), "confirmation.return_url"},
+		{"sbp without confirmation", (&PaymentRequest{Amount: rub("100"), Capture: true}).WithSBP(), "confirmation"},
+		{"cash with redirect", (&PaymentRequest{Amount: rub("100"), Confirmation: redirect, Capture: true}).WithCash(""), "confirmation.type"},
+		{"two-stage sbp", (&PaymentRequest{Amount: rub("100"), Confirmation: redirect}).WithSBP(), "capture"},
+		{"saved cash", (&PaymentRequest{Amount: rub("100"), Confirmation: NewExternalConfirmation(), Capture: true, SavePaymentMethod: true}).WithCash(""), "save_payment_method"},
+		{"invalid method", (&PaymentRequest{Amount: rub("100"), Confirmation: redirect}).WithPhoneBalance(""), "payment_method_data.phone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+
+			if len(tt.param) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+
+				return
+			}
+
+			if e, ok := err.(*Error); !ok || e.Parameter != tt.param {
+				t.Errorf("Validate() = %v, want error for %s", err, tt.param)
+			}
+		})
+	}
+}
+
+func TestValidateForStore(t *testing.T) {
+	store := &Store{PaymentMethods: []string{PaymentMethodBankCard, "future_method"}}
+	req := (&PaymentRequest{Amount: rub("100"), Confirmation: NewQRConfirmation(), Capture: true}).WithSBP()
+
+	if e, ok := req.ValidateForStore(store).(*Error); !ok || e.Parameter != "payment_method_data.type" {
+		t.Errorf("ValidateForStore() = %v, want disabled method error", e)
+	}
+
+	store.PaymentMethods = append(store.PaymentMethods, PaymentMethodSBP)
+
+	if err := req.ValidateForStore(store); err != nil {
+		t.Errorf("ValidateForStore() = %v", err)
+	}
+
+	if unknown := store.UnknownPaymentMethods(); len(unknown) != 1 || unknown[0] != "future_method" {
+		t.Errorf("UnknownPaymentMethods() = %v", unknown)
+	}
+}
+
+func TestCreatePaymentSkipsCompatibilityTable(t *testing.T) {
+	y := &Yandex{HttpClient: &replyDoer{body: `{"id":"p1","status":"pending"}`}}
+
+	// Two-stage SBP payments are rejected by the table, the API has the
+	// final word.
+	req := (&PaymentRequest{Amount: rub("100"), Confirmation: NewQRConfirmation()}).WithSBP()
+
+	if req.Validate() == nil {
+		t.Fatal("Validate() accepted two-stage sbp payment")
+	}
+
+	p, err := y.CreatePayment("key", req)
+
+	if err != nil || p.Id != "p1" {
+		t.Errorf("CreatePayment() = %v, %v", p, err)
+	}
+}
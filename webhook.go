@@ -3,8 +3,18 @@ package yandex
 import (
 	"encoding/json"
 	"log"
+)
 
-	"github.com/google/go-querystring/query"
+const (
+	EventPaymentWaitingForCapture string = "payment.waiting_for_capture"
+	EventPaymentSucceeded         string = "payment.succeeded"
+	EventPaymentCanceled          string = "payment.canceled"
+	EventRefundSucceeded          string = "refund.succeeded"
+	EventDealClosed               string = "deal.closed"
+	EventInvoiceSucceeded         string = "invoice.succeeded"
+	EventInvoiceCanceled          string = "invoice.canceled"
+	EventPayoutSucceeded          string = "payout.succeeded"
+	EventPayoutCanceled           string = "payout.canceled"
 )
 
 type Webhook struct {
@@ -18,20 +28,21 @@ type WebhooksListResponse struct {
 	Items []*Webhook `json:"items"`
 }
 
-func (y *Yandex) SubscribeToWebhook(idempKey string, req *Webhook) (*Webhook, error) {
-	q, err := query.Values(req)
-
-	if err != nil {
-		log.Printf("Failed creating query: %v\n", err)
-		return nil, err
-	}
+type Notification struct {
+	Type   string          `json:"type"`
+	Event  string          `json:"event"`
+	Object json.RawMessage `json:"object"`
+}
 
+func (y *Yandex) SubscribeToWebhook(idempKey string, req *Webhook) (*Webhook, error) {
 	r := &HttpRequest{
 		Method:         "POST",
 		Path:           "/webhooks",
+		BaseURL:        y.BaseURL,
+		HttpClient:     y.HttpClient,
 		IdempotenceKey: idempKey,
 		OAuthToken:     y.OAuthToken,
-		Data:           q,
+		Body:           req,
 	}
 
 	bytes, err := r.SendRequest()
@@ -54,6 +65,8 @@ func (y *Yandex) GetWebhooksList() (*WebhooksListResponse, error) {
 	r := &HttpRequest{
 		Method:     "GET",
 		Path:       "/webhooks",
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
 		OAuthToken: y.OAuthToken,
 	}
 
@@ -77,6 +90,8 @@ func (y *Yandex) DeleteWebhook(id string) error {
 	r := &HttpRequest{
 		Method:     "DELETE",
 		Path:       "/webhooks/" + id,
+		BaseURL:    y.BaseURL,
+		HttpClient: y.HttpClient,
 		OAuthToken: y.OAuthToken,
 	}
 
@@ -88,3 +103,36 @@ func (y *Yandex) DeleteWebhook(id string) error {
 
 	return nil
 }
+
+func ParseNotification(body []byte) (*Notification, error) {
+	res := &Notification{}
+
+	if err := json.Unmarshal(body, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (n *Notification) Payment() (*Payment, error) {
+	res := &Payment{}
+
+	if err := json.Unmarshal(n.Object, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (n *Notification) Refund() (*Refund, error) {
+	res := &Refund{}
+
+	if err := json.Unmarshal(n.Object, res); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	return res, nil
+}
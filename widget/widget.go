@@ -0,0 +1,173 @@
+package widget
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+const (
+	ScriptURL          string = "https://yookassa.ru/checkout-widget/v1/checkout-widget.js"
+	DefaultContainerId string = "payment-form"
+)
+
+var ErrNoConfirmationToken = errors.New("widget: payment has no embedded confirmation token")
+
+var callbackName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+var Snippet = template.Must(template.New("widget").Parse(`<div id="{{.ContainerId}}"></div>
+<script src="{{.ScriptURL}}" nonce="{{.Nonce}}"></script>
+<script nonce="{{.Nonce}}">
+(function () {
+	var config = {{.Config}};
+{{- if .ErrorCallback}}
+	config.error_callback = function (error) { {{.ErrorCallback}}(error); };
+{{- end}}
+	var checkout = new window.YooMoneyCheckoutWidget(config);
+{{- if .SuccessCallback}}
+	checkout.on('success', function () { {{.SuccessCallback}}(); });
+{{- end}}
+{{- if .FailCallback}}
+	checkout.on('fail', function () { {{.FailCallback}}(); });
+{{- end}}
+	checkout.render({{.ContainerId}});
+})();
+</script>
+`))
+
+type Colors struct {
+	ControlPrimary        string `json:"control_primary,omitempty"`
+	ControlPrimaryContent string `json:"control_primary_content,omitempty"`
+	ControlSecondary      string `json:"control_secondary,omitempty"`
+	Background            string `json:"background,omitempty"`
+	Border                string `json:"border,omitempty"`
+	Text                  string `json:"text,omitempty"`
+}
+
+type Customization struct {
+	Modal  bool    `json:"modal,omitempty"`
+	Colors *Colors `json:"colors,omitempty"`
+}
+
+type Config struct {
+	ConfirmationToken string         `json:"confirmation_token"`
+	ReturnUrl         string         `json:"return_url,omitempty"`
+	Customization     *Customization `json:"customization,omitempty"`
+}
+
+type Options struct {
+	ContainerId     string
+	ReturnUrl       string
+	Modal           bool
+	Colors          *Colors
+	Nonce           string
+	ErrorCallback   string
+	SuccessCallback string
+	FailCallback    string
+}
+
+type Data struct {
+	ScriptURL       string
+	ContainerId     string
+	Nonce           string
+	Config          *Config
+	ErrorCallback   template.JS
+	SuccessCallback template.JS
+	FailCallback    template.JS
+}
+
+func NewData(p *yandex.Payment, opts *Options) (*Data, error) {
+	if p == nil || p.Confirmation == nil || p.Confirmation.Type != yandex.ConfirmationEmbedded || len(p.Confirmation.ConfirmationToken) == 0 {
+		return nil, ErrNoConfirmationToken
+	}
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	d := &Data{
+		ScriptURL:   ScriptURL,
+		ContainerId: opts.ContainerId,
+		Nonce:       opts.Nonce,
+		Config: &Config{
+			ConfirmationToken: p.Confirmation.ConfirmationToken,
+			ReturnUrl:         opts.ReturnUrl,
+		},
+	}
+
+	if len(d.ContainerId) == 0 {
+		d.ContainerId = DefaultContainerId
+	}
+
+	if len(d.Nonce) == 0 {
+		nonce, err := NewNonce()
+
+		if err != nil {
+			return nil, err
+		}
+
+		d.Nonce = nonce
+	}
+
+	if opts.Modal || opts.Colors != nil {
+		d.Config.Customization = &Customization{
+			Modal:  opts.Modal,
+			Colors: opts.Colors,
+		}
+	}
+
+	callbacks := []struct {
+		name string
+		dst  *template.JS
+	}{
+		{opts.ErrorCallback, &d.ErrorCallback},
+		{opts.SuccessCallback, &d.SuccessCallback},
+		{opts.FailCallback, &d.FailCallback},
+	}
+
+	for _, c := range callbacks {
+		if len(c.name) == 0 {
+			continue
+		}
+
+		if !callbackName.MatchString(c.name) {
+			return nil, fmt.Errorf("widget: invalid callback name %q", c.name)
+		}
+
+		*c.dst = template.JS(c.name)
+	}
+
+	return d, nil
+}
+
+func Render(p *yandex.Payment, opts *Options) (template.HTML, error) {
+	d, err := NewData(p, opts)
+
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := Snippet.Execute(buf, d); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil
+}
+
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,97 @@
+package widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func embeddedPayment(token string) *yandex.Payment {
+	return &yandex.Payment{
+		Confirmation: &yandex.Confirmation{
+			Type:              yandex.ConfirmationEmbedded,
+			ConfirmationToken: token,
+		},
+	}
+}
+
+func TestNewDataErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *yandex.Payment
+		opts *Options
+	}{
+		{"nil payment", nil, nil},
+		{"no confirmation", &yandex.Payment{}, nil},
+		{"redirect", &yandex.Payment{Confirmation: &yandex.Confirmation{Type: yandex.ConfirmationRedirect}}, nil},
+		{"no token", embeddedPayment(""), nil},
+		{"script in callback", embeddedPayment("ct-1"), &Options{ErrorCallback: "alert(1)"}},
+		{"callback with spaces", embeddedPayment("ct-1"), &Options{SuccessCallback: "on success"}},
+		{"callback ending with dot", embeddedPayment("ct-1"), &Options{FailCallback: "app."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d, err := NewData(tt.p, tt.opts); err == nil {
+				t.Errorf("NewData() = %+v", d)
+			}
+		})
+	}
+}
+
+func TestNewData(t *testing.T) {
+	d, err := NewData(embeddedPayment("ct-1"), nil)
+
+	if err != nil {
+		t.Fatalf("NewData() = %v", err)
+	}
+
+	if d.ContainerId != DefaultContainerId || len(d.Nonce) == 0 || d.Config.ConfirmationToken != "ct-1" || d.Config.Customization != nil {
+		t.Errorf("NewData() = %+v", d)
+	}
+
+	other, _ := NewData(embeddedPayment("ct-1"), nil)
+
+	if other.Nonce == d.Nonce {
+		t.Error("NewData() reuses nonce")
+	}
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render(embeddedPayment("ct-1"), &Options{
+		ContainerId:     "checkout",
+		ReturnUrl:       "https://example.com/return?a=1&b=2",
+		Modal:           true,
+		Colors:          &Colors{ControlPrimary: "#00BF96"},
+		Nonce:           "abc",
+		ErrorCallback:   "app.onError",
+		SuccessCallback: "onSuccess",
+	})
+
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+
+	s := string(html)
+
+	for _, want := range []string{
+		`<div id="checkout"></div>`,
+		`<script src="` + ScriptURL + `" nonce="abc"></script>`,
+		`"confirmation_token":"ct-1"`,
+		`"modal":true`,
+		`"control_primary":"#00BF96"`,
+		`"return_url":"https://example.com/return?a=1\u0026b=2"`,
+		`app.onError(error);`,
+		`checkout.on('success', function () { onSuccess(); });`,
+		`checkout.render("checkout");`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Render() doesn't contain %s:\n%s", want, s)
+		}
+	}
+
+	if strings.Contains(s, "'fail'") {
+		t.Error("Render() subscribes to fail without callback")
+	}
+}
@@ -12,20 +12,36 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+const DefaultBaseURL string = "https://payment.yandex.net/api/v3"
+
+type Doer interface {
+	Do(req *fasthttp.Request, res *fasthttp.Response) error
+}
+
 type Yandex struct {
 	ShopId     string
 	SecretKey  string
 	OAuthToken string
+	BaseURL    string
+	HttpClient Doer
+
+	// AgentId and AgentSecretKey authenticate the payouts gateway,
+	// which is a separate account from the shop.
+	AgentId        string
+	AgentSecretKey string
 }
 
 type HttpRequest struct {
 	Path           string
 	Method         string
+	BaseURL        string
+	HttpClient     Doer
 	ShopId         string
 	SecretKey      string
 	IdempotenceKey string
 	OAuthToken     string
 	Data           url.Values
+	Body           interface{}
 }
 
 type ErrorResponse struct {
@@ -42,9 +58,17 @@ type Amount struct {
 }
 
 func (r *HttpRequest) SendRequest() ([]byte, error) {
-	const baseURL string = "https://payment.yandex.net/api/v3"
+	baseURL := r.BaseURL
 
-	c := &fasthttp.Client{}
+	if len(baseURL) == 0 {
+		baseURL = DefaultBaseURL
+	}
+
+	c := r.HttpClient
+
+	if c == nil {
+		c = &fasthttp.Client{}
+	}
 
 	req := fasthttp.AcquireRequest()
 	res := fasthttp.AcquireResponse()
@@ -66,8 +90,15 @@ func (r *HttpRequest) SendRequest() ([]byte, error) {
 
 	if r.Method == "GET" {
 		req.SetRequestURI(fmt.Sprintf("%s%s?%s", baseURL, r.Path, r.Data.Encode()))
-	} else {
-		req.SetBody([]byte(r.Data.Encode()))
+	} else if r.Body != nil {
+		body, err := json.Marshal(r.Body)
+
+		if err != nil {
+			log.Printf("Failed marshaling struct to bytes: %v\n", err)
+			return nil, err
+		}
+
+		req.SetBody(body)
 	}
 
 	if err := c.Do(req, res); err != nil {
@@ -86,11 +117,12 @@ func (r *HttpRequest) SendRequest() ([]byte, error) {
 		}
 
 		return nil, &Error{
-			Code:    res.StatusCode(),
-			ApiCode: e.Code,
-			Message: e.Description,
+			Code:      res.StatusCode(),
+			ApiCode:   e.Code,
+			Message:   e.Description,
+			Parameter: e.Parameter,
 		}
 	}
 
-	return res.Body(), nil
+	return append([]byte(nil), res.Body()...), nil
 }
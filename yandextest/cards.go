@@ -0,0 +1,107 @@
+package yandextest
+
+import (
+	"fmt"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+// Test cards from the YooKassa sandbox, each declined with the same
+// reason as there.
+const (
+	CardSuccess                 string = "5555555555554444"
+	CardSuccess3DS              string = "5555555555554477"
+	Card3DSecureFailed          string = "5555555555554535"
+	CardCallIssuer              string = "5555555555554543"
+	CardExpired                 string = "5555555555554550"
+	CardCountryForbidden        string = "5555555555554568"
+	CardFraudSuspected          string = "5555555555554576"
+	CardGeneralDecline          string = "5555555555554584"
+	CardIdentificationRequired  string = "5555555555554592"
+	CardInsufficientFunds       string = "5555555555554600"
+	CardInvalidCardNumber       string = "5555555555554618"
+	CardInvalidCSC              string = "5555555555554626"
+	CardIssuerUnavailable       string = "5555555555554634"
+	CardPaymentMethodLimit      string = "5555555555554642"
+	CardPaymentMethodRestricted string = "5555555555554659"
+)
+
+func defaultDeclines() map[string]*yandex.CancellationDetails {
+	return map[string]*yandex.CancellationDetails{
+		Card3DSecureFailed:          {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReason3DSecureFailed},
+		CardCallIssuer:              {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonCallIssuer},
+		CardExpired:                 {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonCardExpired},
+		CardCountryForbidden:        {Party: yandex.CancellationPartyYooMoney, Reason: yandex.CancellationReasonCountryForbidden},
+		CardFraudSuspected:          {Party: yandex.CancellationPartyYooMoney, Reason: yandex.CancellationReasonFraudSuspected},
+		CardGeneralDecline:          {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonGeneralDecline},
+		CardIdentificationRequired:  {Party: yandex.CancellationPartyYooMoney, Reason: yandex.CancellationReasonIdentificationRequired},
+		CardInsufficientFunds:       {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonInsufficientFunds},
+		CardInvalidCardNumber:       {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonInvalidCardNumber},
+		CardInvalidCSC:              {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonInvalidCSC},
+		CardIssuerUnavailable:       {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonIssuerUnavailable},
+		CardPaymentMethodLimit:      {Party: yandex.CancellationPartyYooMoney, Reason: yandex.CancellationReasonPaymentMethodLimitExceeded},
+		CardPaymentMethodRestricted: {Party: yandex.CancellationPartyPaymentNetwork, Reason: yandex.CancellationReasonPaymentMethodRestricted},
+	}
+}
+
+func (s *Server) DeclineCard(number, party, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.declines[number] = &yandex.CancellationDetails{
+		Party:  party,
+		Reason: reason,
+	}
+}
+
+func (s *Server) AuthorizePaymentWithCard(id, number string) error {
+	defer s.dispatch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[id]
+
+	if !ok {
+		return fmt.Errorf("payment %s not found", id)
+	}
+
+	if p.Status != "pending" {
+		return fmt.Errorf("payment %s is in %s status", id, p.Status)
+	}
+
+	p.PaymentMethod = responseMethod(&yandex.PaymentMethod{
+		Type: "bank_card",
+		Card: &yandex.Card{
+			Number:      number,
+			ExpiryYear:  "2030",
+			ExpiryMonth: "12",
+		},
+	})
+	p.CardNumber = number
+
+	s.authorizePayment(p)
+
+	return nil
+}
+
+func (s *Server) DeclinePayment(id, party, reason string) error {
+	defer s.dispatch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[id]
+
+	if !ok {
+		return fmt.Errorf("payment %s not found", id)
+	}
+
+	if p.Status != "pending" && p.Status != "waiting_for_capture" {
+		return fmt.Errorf("payment %s is in %s status", id, p.Status)
+	}
+
+	s.cancel(p, party, reason)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package yandextest
+
+import (
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/shopspring/decimal"
+)
+
+func payWithCard(c *yandex.Yandex, number string) (*yandex.Payment, error) {
+	return c.CreatePayment(newId(""), &yandex.PaymentRequest{
+		Amount: &yandex.Amount{
+			Value:    decimal.NewFromInt(100),
+			Currency: "RUB",
+		},
+		Capture: true,
+		PaymentMethodData: &yandex.PaymentMethod{
+			Type: yandex.PaymentMethodBankCard,
+			Card: &yandex.Card{
+				Number:      number,
+				ExpiryYear:  "2030",
+				ExpiryMonth: "12",
+			},
+		},
+	})
+}
+
+func TestCardDeclines(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	tests := []struct {
+		card   string
+		party  string
+		reason string
+	}{
+		{Card3DSecureFailed, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReason3DSecureFailed},
+		{CardCallIssuer, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonCallIssuer},
+		{CardExpired, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonCardExpired},
+		{CardCountryForbidden, yandex.CancellationPartyYooMoney, yandex.CancellationReasonCountryForbidden},
+		{CardFraudSuspected, yandex.CancellationPartyYooMoney, yandex.CancellationReasonFraudSuspected},
+		{CardGeneralDecline, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonGeneralDecline},
+		{CardIdentificationRequired, yandex.CancellationPartyYooMoney, yandex.CancellationReasonIdentificationRequired},
+		{CardInsufficientFunds, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonInsufficientFunds},
+		{CardInvalidCardNumber, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonInvalidCardNumber},
+		{CardInvalidCSC, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonInvalidCSC},
+		{CardIssuerUnavailable, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonIssuerUnavailable},
+		{CardPaymentMethodLimit, yandex.CancellationPartyYooMoney, yandex.CancellationReasonPaymentMethodLimitExceeded},
+		{CardPaymentMethodRestricted, yandex.CancellationPartyPaymentNetwork, yandex.CancellationReasonPaymentMethodRestricted},
+	}
+
+	if len(tests) != len(defaultDeclines()) {
+		t.Fatalf("%d declined cards tested, %d defined", len(tests), len(defaultDeclines()))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			p, err := payWithCard(s.Client(), tt.card)
+
+			if err != nil {
+				t.Fatalf("CreatePayment() = %v", err)
+			}
+
+			if p.Status != yandex.PaymentStatusCanceled || p.CancellationDetails == nil {
+				t.Fatalf("payment is %s, want canceled", p.Status)
+			}
+
+			if d := p.CancellationDetails; d.Party != tt.party || d.Reason != tt.reason {
+				t.Errorf("canceled by %s with %s, want %s with %s", d.Party, d.Reason, tt.party, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCardSuccess(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	for _, card := range []string{CardSuccess, CardSuccess3DS} {
+		p, err := payWithCard(s.Client(), card)
+
+		if err != nil {
+			t.Fatalf("CreatePayment() = %v", err)
+		}
+
+		if p.Status != yandex.PaymentStatusSucceeded {
+			t.Errorf("payment with %s is %s, want succeeded", card, p.Status)
+		}
+	}
+}
+
+func TestDeclineCard(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.DeclineCard(CardSuccess, yandex.CancellationPartyMerchant, yandex.CancellationReasonGeneralDecline)
+
+	p, err := payWithCard(s.Client(), CardSuccess)
+
+	if err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	if p.Status != yandex.PaymentStatusCanceled || p.CancellationDetails.Party != yandex.CancellationPartyMerchant {
+		t.Errorf("payment is %s, want canceled by merchant", p.Status)
+	}
+}
+
+func TestDeclinePayment(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if err := s.DeclinePayment("unknown", yandex.CancellationPartyYooMoney, yandex.CancellationReasonFraudSuspected); err == nil {
+		t.Error("DeclinePayment() of unknown payment succeeded")
+	}
+
+	p, err := payWithCard(s.Client(), CardSuccess)
+
+	if err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	if err := s.DeclinePayment(p.Id, yandex.CancellationPartyYooMoney, yandex.CancellationReasonFraudSuspected); err == nil {
+		t.Error("DeclinePayment() of succeeded payment succeeded")
+	}
+}
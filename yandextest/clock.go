@@ -0,0 +1,50 @@
+package yandextest
+
+import (
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+const PendingTimeout time.Duration = time.Hour
+
+func (s *Server) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now()
+}
+
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	s.offset += d
+	s.expire()
+	s.mu.Unlock()
+
+	s.dispatch()
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().UTC().Add(s.offset)
+}
+
+func (s *Server) timestamp() string {
+	return formatTime(s.now())
+}
+
+func (s *Server) expire() {
+	now := s.now()
+
+	for _, p := range s.payments {
+		switch {
+		case p.Status == "pending" && now.Sub(p.Created) >= PendingTimeout:
+			s.cancel(p, yandex.CancellationPartyYooMoney, yandex.CancellationReasonExpiredOnConfirmation)
+		case p.Status == "waiting_for_capture" && !now.Before(p.Expires):
+			s.cancel(p, yandex.CancellationPartyYooMoney, yandex.CancellationReasonExpiredOnCapture)
+		}
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
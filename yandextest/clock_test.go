@@ -0,0 +1,67 @@
+package yandextest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		name    string
+		capture bool
+		advance time.Duration
+		status  string
+		reason  string
+	}{
+		{"pending before timeout", false, PendingTimeout - time.Minute, yandex.PaymentStatusPending, ""},
+		{"pending expires", false, PendingTimeout, yandex.PaymentStatusCanceled, yandex.CancellationReasonExpiredOnConfirmation},
+		{"authorized before timeout", true, CaptureTimeout - time.Minute, yandex.PaymentStatusWaitingForCapture, ""},
+		{"authorized expires", true, CaptureTimeout, yandex.PaymentStatusCanceled, yandex.CancellationReasonExpiredOnCapture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			p, err := s.Client().CreatePayment(newId(""), newPaymentRequest())
+
+			if err != nil {
+				t.Fatalf("CreatePayment() = %v", err)
+			}
+
+			if tt.capture {
+				if err := s.AuthorizePayment(p.Id); err != nil {
+					t.Fatalf("AuthorizePayment() = %v", err)
+				}
+			}
+
+			before := s.Now()
+			s.Advance(tt.advance)
+
+			if d := s.Now().Sub(before); d < tt.advance {
+				t.Errorf("clock moved by %s, want %s", d, tt.advance)
+			}
+
+			p, err = s.Client().GetPaymentInfo(p.Id)
+
+			if err != nil {
+				t.Fatalf("GetPaymentInfo() = %v", err)
+			}
+
+			if p.Status != tt.status {
+				t.Fatalf("payment is %s, want %s", p.Status, tt.status)
+			}
+
+			if len(tt.reason) == 0 {
+				return
+			}
+
+			if d := p.CancellationDetails; d.Party != yandex.CancellationPartyYooMoney || d.Reason != tt.reason {
+				t.Errorf("canceled by %s with %s, want yoo_money with %s", d.Party, d.Reason, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package yandextest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type Fault struct {
+	// Method and Path restrict the fault to matching requests, Path is
+	// matched as a prefix. Empty values match any request.
+	Method string
+	Path   string
+
+	// Times is the number of requests the fault fires for, zero means
+	// until ClearFaults is called. Attempts the client repeats on its
+	// own after a dropped connection aren't counted.
+	Times int
+
+	Latency    time.Duration
+	Status     int
+	RetryAfter time.Duration
+	Truncate   bool
+	Drop       bool
+
+	// Applied makes the server handle the request before the fault
+	// fires, so the state changes although the client sees a failure.
+	Applied bool
+}
+
+type activeFault struct {
+	Fault
+	left int
+}
+
+// InjectFault copies f, changing it afterwards doesn't affect the server.
+func (s *Server) InjectFault(f *Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &activeFault{
+		Fault: *f,
+		left:  f.Times,
+	})
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+	s.retries = map[string]int{}
+}
+
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if len(f.Method) > 0 && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+
+		if len(f.Path) > 0 && !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		if f.left > 0 {
+			f.left--
+
+			if f.left == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return &f.Fault
+	}
+
+	return nil
+}
+
+// fasthttp repeats GET, HEAD and PUT requests after any connection error,
+// so a dropped request of these methods keeps being dropped until the
+// client runs out of attempts.
+func (s *Server) rememberDrop(r *http.Request, body []byte) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut:
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retries[retryKey(r, body)] = fasthttp.DefaultMaxIdemponentCallAttempts - 1
+}
+
+func (s *Server) isRetryOfDrop(r *http.Request, body []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := retryKey(r, body)
+	left, ok := s.retries[key]
+
+	if !ok {
+		return false
+	}
+
+	if left <= 1 {
+		delete(s.retries, key)
+	} else {
+		s.retries[key] = left - 1
+	}
+
+	return true
+}
+
+func retryKey(r *http.Request, body []byte) string {
+	return r.Method + " " + r.URL.RequestURI() + " " + r.Header.Get("Idempotence-Key") + " " + string(body)
+}
+
+func writeFault(w http.ResponseWriter, f *Fault) {
+	switch f.Status {
+	case http.StatusTooManyRequests:
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter/time.Second)))
+		}
+
+		writeError(w, &apiError{f.Status, "too_many_requests", "Too many requests. Try again later", ""})
+	case http.StatusInternalServerError:
+		writeError(w, &apiError{f.Status, "internal_server_error", "Internal server error. Try again later", ""})
+	default:
+		writeError(w, &apiError{f.Status, strings.ToLower(strings.Replace(http.StatusText(f.Status), " ", "_", -1)), http.StatusText(f.Status), ""})
+	}
+}
+
+func dropConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	conn, buf, err := hj.Hijack()
+
+	if err != nil {
+		return
+	}
+
+	// A connection closed before any byte is sent looks like a stale
+	// keep-alive connection, fasthttp would silently repeat even a POST.
+	buf.WriteString("HTTP/1.1 200 OK\r\n")
+	buf.Flush()
+	conn.Close()
+}
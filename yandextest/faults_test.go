@@ -0,0 +1,183 @@
+package yandextest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/shopspring/decimal"
+)
+
+func newPaymentRequest() *yandex.PaymentRequest {
+	return &yandex.PaymentRequest{
+		Amount: &yandex.Amount{
+			Value:    decimal.NewFromInt(100),
+			Currency: "RUB",
+		},
+		Confirmation: &yandex.Confirmation{
+			Type:      yandex.ConfirmationRedirect,
+			ReturnUrl: "https://example.com",
+		},
+	}
+}
+
+func apiCode(err error) (int, string) {
+	e, ok := err.(*yandex.Error)
+
+	if !ok {
+		return 0, ""
+	}
+
+	return e.Code, e.ApiCode
+}
+
+func TestFaultStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  *Fault
+		code   int
+		status string
+	}{
+		{"internal error", &Fault{Status: http.StatusInternalServerError}, http.StatusInternalServerError, "internal_server_error"},
+		{"too many requests", &Fault{Status: http.StatusTooManyRequests}, http.StatusTooManyRequests, "too_many_requests"},
+		{"other status", &Fault{Status: http.StatusServiceUnavailable}, http.StatusServiceUnavailable, "service_unavailable"},
+		{"matching method", &Fault{Method: "post", Status: http.StatusInternalServerError}, http.StatusInternalServerError, "internal_server_error"},
+		{"matching path", &Fault{Path: "/payments", Status: http.StatusInternalServerError}, http.StatusInternalServerError, "internal_server_error"},
+		{"other method", &Fault{Method: http.MethodGet, Status: http.StatusInternalServerError}, 0, ""},
+		{"other path", &Fault{Path: "/refunds", Status: http.StatusInternalServerError}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			s.InjectFault(tt.fault)
+
+			_, err := s.Client().CreatePayment(newId(""), newPaymentRequest())
+
+			if tt.code == 0 {
+				if err != nil {
+					t.Errorf("CreatePayment() = %v", err)
+				}
+
+				return
+			}
+
+			if code, status := apiCode(err); code != tt.code || status != tt.status {
+				t.Errorf("CreatePayment() = %v, want %d %s", err, tt.code, tt.status)
+			}
+		})
+	}
+}
+
+func TestFaultTimes(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	f := &Fault{Status: http.StatusInternalServerError, Times: 2}
+	s.InjectFault(f)
+
+	for i, fails := range []bool{true, true, false} {
+		_, err := s.Client().CreatePayment(newId(""), newPaymentRequest())
+
+		if (err != nil) != fails {
+			t.Errorf("request %d: CreatePayment() = %v, want failure %t", i, err, fails)
+		}
+	}
+
+	if f.Times != 2 {
+		t.Errorf("fault Times changed to %d", f.Times)
+	}
+
+	s.InjectFault(&Fault{Status: http.StatusInternalServerError})
+	s.ClearFaults()
+
+	if _, err := s.Client().CreatePayment(newId(""), newPaymentRequest()); err != nil {
+		t.Errorf("CreatePayment() after ClearFaults() = %v", err)
+	}
+}
+
+func TestFaultTruncate(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.InjectFault(&Fault{Truncate: true, Times: 1})
+
+	_, err := s.Client().CreatePayment(newId(""), newPaymentRequest())
+
+	if err == nil {
+		t.Fatal("CreatePayment() with truncated response succeeded")
+	}
+
+	if _, ok := err.(*yandex.Error); ok {
+		t.Errorf("CreatePayment() = %v, want decoding error", err)
+	}
+}
+
+func TestFaultDrop(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied bool
+		stored  int
+	}{
+		{"before handling", false, 0},
+		{"after handling", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			s.InjectFault(&Fault{Method: http.MethodPost, Drop: true, Applied: tt.applied, Times: 1})
+
+			key := newId("")
+			c := s.Client()
+
+			if _, err := c.CreatePayment(key, newPaymentRequest()); err == nil {
+				t.Fatal("CreatePayment() over dropped connection succeeded")
+			}
+
+			if n := len(s.payments); n != tt.stored {
+				t.Errorf("%d payments created by dropped request, want %d", n, tt.stored)
+			}
+
+			p, err := c.CreatePayment(key, newPaymentRequest())
+
+			if err != nil {
+				t.Fatalf("retried CreatePayment() = %v", err)
+			}
+
+			if n := len(s.payments); n != 1 {
+				t.Errorf("%d payments created after retry, want 1", n)
+			}
+
+			if _, ok := s.Payment(p.Id); !ok {
+				t.Errorf("payment %s not found", p.Id)
+			}
+		})
+	}
+}
+
+func TestFaultDropIdempotent(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	p, err := c.CreatePayment(newId(""), newPaymentRequest())
+
+	if err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	s.InjectFault(&Fault{Method: http.MethodGet, Drop: true, Times: 1})
+
+	if _, err := c.GetPaymentInfo(p.Id); err == nil {
+		t.Fatal("GetPaymentInfo() over dropped connection succeeded")
+	}
+
+	if _, err := c.GetPaymentInfo(p.Id); err != nil {
+		t.Errorf("GetPaymentInfo() after drop = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package yandextest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func (s *Server) ActivatePaymentMethod(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.methods[id]
+
+	if !ok {
+		return fmt.Errorf("payment method %s not found", id)
+	}
+
+	m.Status = yandex.SavedPaymentMethodActive
+	m.Saved = true
+	m.Confirmation = nil
+
+	return nil
+}
+
+func (s *Server) RevokePaymentMethod(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.methods[id]
+
+	if !ok {
+		return fmt.Errorf("payment method %s not found", id)
+	}
+
+	m.Status = yandex.SavedPaymentMethodInactive
+	m.Saved = false
+
+	return nil
+}
+
+func (s *Server) createPaymentMethod(body []byte) (int, interface{}, *apiError) {
+	req := &yandex.SavePaymentMethodRequest{}
+
+	if e := decodeBody(body, req); e != nil {
+		return 0, nil, e
+	}
+
+	if req.Type != yandex.PaymentMethodBankCard {
+		return 0, nil, invalidRequest("Only bank_card payment methods can be saved", "type")
+	}
+
+	if req.Confirmation == nil || req.Confirmation.Type != yandex.ConfirmationRedirect || len(req.Confirmation.ReturnUrl) == 0 {
+		return 0, nil, invalidRequest("Redirect confirmation with return URL is required", "confirmation")
+	}
+
+	m := &yandex.SavedPaymentMethod{
+		Type:   req.Type,
+		Id:     newId(""),
+		Status: yandex.SavedPaymentMethodPending,
+		Holder: &yandex.Holder{
+			AccountId: s.ShopId,
+			GatewayId: GatewayId,
+		},
+	}
+
+	m.Confirmation = &yandex.Confirmation{
+		Type:            yandex.ConfirmationRedirect,
+		ReturnUrl:       req.Confirmation.ReturnUrl,
+		ConfirmationUrl: s.URL + "/checkout/payment-methods/confirm?id=" + m.Id,
+	}
+
+	if req.Card != nil {
+		if res := responseMethod(&yandex.PaymentMethod{Type: req.Type, Card: req.Card}); res != nil {
+			m.Card = res.Card
+			m.Title = res.Title
+		}
+	}
+
+	s.methods[m.Id] = m
+
+	return http.StatusOK, m, nil
+}
+
+func (s *Server) getPaymentMethod(id string) (int, interface{}, *apiError) {
+	m, ok := s.methods[id]
+
+	if !ok {
+		return 0, nil, notFound("Payment method not found")
+	}
+
+	return http.StatusOK, m, nil
+}
+
+func (s *Server) savePaymentMethod(p *payment) {
+	s.methods[p.PaymentMethod.Id] = &yandex.SavedPaymentMethod{
+		Type:   p.PaymentMethod.Type,
+		Id:     p.PaymentMethod.Id,
+		Saved:  true,
+		Status: yandex.SavedPaymentMethodActive,
+		Title:  p.PaymentMethod.Title,
+		Card:   p.PaymentMethod.Card,
+		Holder: &yandex.Holder{
+			AccountId: s.ShopId,
+			GatewayId: GatewayId,
+		},
+	}
+}
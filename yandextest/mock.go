@@ -0,0 +1,393 @@
+package yandextest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+type Mock struct {
+	CreatePaymentFunc            func(idempKey string, req *yandex.PaymentRequest) (*yandex.Payment, error)
+	GetPaymentInfoFunc           func(id string) (*yandex.Payment, error)
+	ConfirmPaymentFunc           func(idempKey, id string, req *yandex.PaymentConfirmationRequest) (*yandex.Payment, error)
+	CancelPaymentFunc            func(idempKey, id string) (*yandex.Payment, error)
+	SavePaymentMethodFunc        func(idempKey string, req *yandex.SavePaymentMethodRequest) (*yandex.SavedPaymentMethod, error)
+	GetSavedPaymentMethodFunc    func(id string) (*yandex.SavedPaymentMethod, error)
+	ChargeSavedPaymentMethodFunc func(idempKey, methodId string, req *yandex.PaymentRequest) (*yandex.Payment, error)
+	CreateRefundFunc             func(idempKey string, req *yandex.RefundRequest) (*yandex.Refund, error)
+	GetRefundInfoFunc            func(id string) (*yandex.Refund, error)
+	CreateReceiptFunc            func(idempKey string, req *yandex.ReceiptRequest) (*yandex.Receipt, error)
+	GetReceiptInfoFunc           func(id string) (*yandex.Receipt, error)
+	CreateInvoiceFunc            func(idempKey string, req *yandex.InvoiceRequest) (*yandex.Invoice, error)
+	GetInvoiceInfoFunc           func(id string) (*yandex.Invoice, error)
+	CancelInvoiceFunc            func(idempKey, id string) (*yandex.Invoice, error)
+	CreateDealFunc               func(idempKey string, req *yandex.DealRequest) (*yandex.Deal, error)
+	GetDealInfoFunc              func(id string) (*yandex.Deal, error)
+	GetDealsListFunc             func(req *yandex.DealsListRequest) (*yandex.DealsListResponse, error)
+	CreatePayoutFunc             func(idempKey string, req *yandex.PayoutRequest) (*yandex.Payout, error)
+	GetPayoutInfoFunc            func(id string) (*yandex.Payout, error)
+	GetPayoutsListFunc           func(req *yandex.PayoutsListRequest) (*yandex.PayoutsListResponse, error)
+	CreatePersonalDataFunc       func(idempKey string, req *yandex.PersonalDataRequest) (*yandex.PersonalData, error)
+	GetPersonalDataFunc          func(id string) (*yandex.PersonalData, error)
+	GetSBPBanksFunc              func() (*yandex.SBPBanksListResponse, error)
+	CreateSelfEmployedFunc       func(idempKey string, req *yandex.SelfEmployedRequest) (*yandex.SelfEmployed, error)
+	GetSelfEmployedFunc          func(id string) (*yandex.SelfEmployed, error)
+	SubscribeToWebhookFunc       func(idempKey string, req *yandex.Webhook) (*yandex.Webhook, error)
+	GetWebhooksListFunc          func() (*yandex.WebhooksListResponse, error)
+	DeleteWebhookFunc            func(id string) error
+	GetStoreInfoFunc             func() (*yandex.Store, error)
+
+	mu    sync.Mutex
+	calls []*Call
+}
+
+var _ yandex.Client = (*Mock)(nil)
+
+func (m *Mock) Calls() []*Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*Call(nil), m.calls...)
+}
+
+func (m *Mock) CallsTo(method string) []*Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := []*Call{}
+
+	for _, c := range m.calls {
+		if c.Method == method {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+func (m *Mock) LastCall(method string) *Call {
+	calls := m.CallsTo(method)
+
+	if len(calls) == 0 {
+		return nil
+	}
+
+	return calls[len(calls)-1]
+}
+
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, &Call{
+		Method: method,
+		Args:   args,
+	})
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("yandextest: %s is not stubbed", method)
+}
+
+func (m *Mock) CreatePayment(idempKey string, req *yandex.PaymentRequest) (*yandex.Payment, error) {
+	m.record("CreatePayment", idempKey, req)
+
+	if m.CreatePaymentFunc == nil {
+		return nil, notStubbed("CreatePayment")
+	}
+
+	return m.CreatePaymentFunc(idempKey, req)
+}
+
+func (m *Mock) GetPaymentInfo(id string) (*yandex.Payment, error) {
+	m.record("GetPaymentInfo", id)
+
+	if m.GetPaymentInfoFunc == nil {
+		return nil, notStubbed("GetPaymentInfo")
+	}
+
+	return m.GetPaymentInfoFunc(id)
+}
+
+func (m *Mock) ConfirmPayment(idempKey, id string, req *yandex.PaymentConfirmationRequest) (*yandex.Payment, error) {
+	m.record("ConfirmPayment", idempKey, id, req)
+
+	if m.ConfirmPaymentFunc == nil {
+		return nil, notStubbed("ConfirmPayment")
+	}
+
+	return m.ConfirmPaymentFunc(idempKey, id, req)
+}
+
+func (m *Mock) CancelPayment(idempKey, id string) (*yandex.Payment, error) {
+	m.record("CancelPayment", idempKey, id)
+
+	if m.CancelPaymentFunc == nil {
+		return nil, notStubbed("CancelPayment")
+	}
+
+	return m.CancelPaymentFunc(idempKey, id)
+}
+
+func (m *Mock) SavePaymentMethod(idempKey string, req *yandex.SavePaymentMethodRequest) (*yandex.SavedPaymentMethod, error) {
+	m.record("SavePaymentMethod", idempKey, req)
+
+	if m.SavePaymentMethodFunc == nil {
+		return nil, notStubbed("SavePaymentMethod")
+	}
+
+	return m.SavePaymentMethodFunc(idempKey, req)
+}
+
+func (m *Mock) GetSavedPaymentMethod(id string) (*yandex.SavedPaymentMethod, error) {
+	m.record("GetSavedPaymentMethod", id)
+
+	if m.GetSavedPaymentMethodFunc == nil {
+		return nil, notStubbed("GetSavedPaymentMethod")
+	}
+
+	return m.GetSavedPaymentMethodFunc(id)
+}
+
+func (m *Mock) ChargeSavedPaymentMethod(idempKey, methodId string, req *yandex.PaymentRequest) (*yandex.Payment, error) {
+	m.record("ChargeSavedPaymentMethod", idempKey, methodId, req)
+
+	if m.ChargeSavedPaymentMethodFunc == nil {
+		return nil, notStubbed("ChargeSavedPaymentMethod")
+	}
+
+	return m.ChargeSavedPaymentMethodFunc(idempKey, methodId, req)
+}
+
+func (m *Mock) CreateRefund(idempKey string, req *yandex.RefundRequest) (*yandex.Refund, error) {
+	m.record("CreateRefund", idempKey, req)
+
+	if m.CreateRefundFunc == nil {
+		return nil, notStubbed("CreateRefund")
+	}
+
+	return m.CreateRefundFunc(idempKey, req)
+}
+
+func (m *Mock) GetRefundInfo(id string) (*yandex.Refund, error) {
+	m.record("GetRefundInfo", id)
+
+	if m.GetRefundInfoFunc == nil {
+		return nil, notStubbed("GetRefundInfo")
+	}
+
+	return m.GetRefundInfoFunc(id)
+}
+
+func (m *Mock) CreateReceipt(idempKey string, req *yandex.ReceiptRequest) (*yandex.Receipt, error) {
+	m.record("CreateReceipt", idempKey, req)
+
+	if m.CreateReceiptFunc == nil {
+		return nil, notStubbed("CreateReceipt")
+	}
+
+	return m.CreateReceiptFunc(idempKey, req)
+}
+
+func (m *Mock) GetReceiptInfo(id string) (*yandex.Receipt, error) {
+	m.record("GetReceiptInfo", id)
+
+	if m.GetReceiptInfoFunc == nil {
+		return nil, notStubbed("GetReceiptInfo")
+	}
+
+	return m.GetReceiptInfoFunc(id)
+}
+
+func (m *Mock) CreateInvoice(idempKey string, req *yandex.InvoiceRequest) (*yandex.Invoice, error) {
+	m.record("CreateInvoice", idempKey, req)
+
+	if m.CreateInvoiceFunc == nil {
+		return nil, notStubbed("CreateInvoice")
+	}
+
+	return m.CreateInvoiceFunc(idempKey, req)
+}
+
+func (m *Mock) GetInvoiceInfo(id string) (*yandex.Invoice, error) {
+	m.record("GetInvoiceInfo", id)
+
+	if m.GetInvoiceInfoFunc == nil {
+		return nil, notStubbed("GetInvoiceInfo")
+	}
+
+	return m.GetInvoiceInfoFunc(id)
+}
+
+func (m *Mock) CancelInvoice(idempKey, id string) (*yandex.Invoice, error) {
+	m.record("CancelInvoice", idempKey, id)
+
+	if m.CancelInvoiceFunc == nil {
+		return nil, notStubbed("CancelInvoice")
+	}
+
+	return m.CancelInvoiceFunc(idempKey, id)
+}
+
+func (m *Mock) CreateDeal(idempKey string, req *yandex.DealRequest) (*yandex.Deal, error) {
+	m.record("CreateDeal", idempKey, req)
+
+	if m.CreateDealFunc == nil {
+		return nil, notStubbed("CreateDeal")
+	}
+
+	return m.CreateDealFunc(idempKey, req)
+}
+
+func (m *Mock) GetDealInfo(id string) (*yandex.Deal, error) {
+	m.record("GetDealInfo", id)
+
+	if m.GetDealInfoFunc == nil {
+		return nil, notStubbed("GetDealInfo")
+	}
+
+	return m.GetDealInfoFunc(id)
+}
+
+func (m *Mock) GetDealsList(req *yandex.DealsListRequest) (*yandex.DealsListResponse, error) {
+	m.record("GetDealsList", req)
+
+	if m.GetDealsListFunc == nil {
+		return nil, notStubbed("GetDealsList")
+	}
+
+	return m.GetDealsListFunc(req)
+}
+
+func (m *Mock) CreatePayout(idempKey string, req *yandex.PayoutRequest) (*yandex.Payout, error) {
+	m.record("CreatePayout", idempKey, req)
+
+	if m.CreatePayoutFunc == nil {
+		return nil, notStubbed("CreatePayout")
+	}
+
+	return m.CreatePayoutFunc(idempKey, req)
+}
+
+func (m *Mock) GetPayoutInfo(id string) (*yandex.Payout, error) {
+	m.record("GetPayoutInfo", id)
+
+	if m.GetPayoutInfoFunc == nil {
+		return nil, notStubbed("GetPayoutInfo")
+	}
+
+	return m.GetPayoutInfoFunc(id)
+}
+
+func (m *Mock) GetPayoutsList(req *yandex.PayoutsListRequest) (*yandex.PayoutsListResponse, error) {
+	m.record("GetPayoutsList", req)
+
+	if m.GetPayoutsListFunc == nil {
+		return nil, notStubbed("GetPayoutsList")
+	}
+
+	return m.GetPayoutsListFunc(req)
+}
+
+func (m *Mock) CreatePersonalData(idempKey string, req *yandex.PersonalDataRequest) (*yandex.PersonalData, error) {
+	m.record("CreatePersonalData", idempKey, req)
+
+	if m.CreatePersonalDataFunc == nil {
+		return nil, notStubbed("CreatePersonalData")
+	}
+
+	return m.CreatePersonalDataFunc(idempKey, req)
+}
+
+func (m *Mock) GetPersonalData(id string) (*yandex.PersonalData, error) {
+	m.record("GetPersonalData", id)
+
+	if m.GetPersonalDataFunc == nil {
+		return nil, notStubbed("GetPersonalData")
+	}
+
+	return m.GetPersonalDataFunc(id)
+}
+
+func (m *Mock) GetSBPBanks() (*yandex.SBPBanksListResponse, error) {
+	m.record("GetSBPBanks")
+
+	if m.GetSBPBanksFunc == nil {
+		return nil, notStubbed("GetSBPBanks")
+	}
+
+	return m.GetSBPBanksFunc()
+}
+
+func (m *Mock) CreateSelfEmployed(idempKey string, req *yandex.SelfEmployedRequest) (*yandex.SelfEmployed, error) {
+	m.record("CreateSelfEmployed", idempKey, req)
+
+	if m.CreateSelfEmployedFunc == nil {
+		return nil, notStubbed("CreateSelfEmployed")
+	}
+
+	return m.CreateSelfEmployedFunc(idempKey, req)
+}
+
+func (m *Mock) GetSelfEmployed(id string) (*yandex.SelfEmployed, error) {
+	m.record("GetSelfEmployed", id)
+
+	if m.GetSelfEmployedFunc == nil {
+		return nil, notStubbed("GetSelfEmployed")
+	}
+
+	return m.GetSelfEmployedFunc(id)
+}
+
+func (m *Mock) SubscribeToWebhook(idempKey string, req *yandex.Webhook) (*yandex.Webhook, error) {
+	m.record("SubscribeToWebhook", idempKey, req)
+
+	if m.SubscribeToWebhookFunc == nil {
+		return nil, notStubbed("SubscribeToWebhook")
+	}
+
+	return m.SubscribeToWebhookFunc(idempKey, req)
+}
+
+func (m *Mock) GetWebhooksList() (*yandex.WebhooksListResponse, error) {
+	m.record("GetWebhooksList")
+
+	if m.GetWebhooksListFunc == nil {
+		return nil, notStubbed("GetWebhooksList")
+	}
+
+	return m.GetWebhooksListFunc()
+}
+
+func (m *Mock) DeleteWebhook(id string) error {
+	m.record("DeleteWebhook", id)
+
+	if m.DeleteWebhookFunc == nil {
+		return notStubbed("DeleteWebhook")
+	}
+
+	return m.DeleteWebhookFunc(id)
+}
+
+func (m *Mock) GetStoreInfo() (*yandex.Store, error) {
+	m.record("GetStoreInfo")
+
+	if m.GetStoreInfoFunc == nil {
+		return nil, notStubbed("GetStoreInfo")
+	}
+
+	return m.GetStoreInfoFunc()
+}
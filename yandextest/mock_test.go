@@ -0,0 +1,92 @@
+package yandextest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func TestMockStubs(t *testing.T) {
+	m := &Mock{
+		GetPaymentInfoFunc: func(id string) (*yandex.Payment, error) {
+			return &yandex.Payment{Id: id, Status: yandex.PaymentStatusSucceeded}, nil
+		},
+		DeleteWebhookFunc: func(id string) error {
+			return errors.New("webhook " + id + " not found")
+		},
+	}
+
+	var c yandex.Client = m
+
+	p, err := c.GetPaymentInfo("p1")
+
+	if err != nil || p.Id != "p1" {
+		t.Errorf("GetPaymentInfo() = %v, %v", p, err)
+	}
+
+	if err := c.DeleteWebhook("wh1"); err == nil || err.Error() != "webhook wh1 not found" {
+		t.Errorf("DeleteWebhook() = %v", err)
+	}
+
+	if _, err := c.CreatePayment("key", &yandex.PaymentRequest{}); err == nil {
+		t.Error("CreatePayment() without stub succeeded")
+	}
+}
+
+func TestMockCalls(t *testing.T) {
+	m := &Mock{}
+	req := &yandex.RefundRequest{PaymentId: "p1"}
+
+	m.GetPaymentInfo("p1")
+	m.CreateRefund("key1", req)
+	m.GetPaymentInfo("p2")
+
+	tests := []struct {
+		method string
+		calls  int
+		last   []interface{}
+	}{
+		{"GetPaymentInfo", 2, []interface{}{"p2"}},
+		{"CreateRefund", 1, []interface{}{"key1", req}},
+		{"CreatePayment", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if n := len(m.CallsTo(tt.method)); n != tt.calls {
+				t.Errorf("%d calls, want %d", n, tt.calls)
+			}
+
+			last := m.LastCall(tt.method)
+
+			if tt.last == nil {
+				if last != nil {
+					t.Errorf("LastCall() = %v, want nil", last)
+				}
+
+				return
+			}
+
+			if last == nil || len(last.Args) != len(tt.last) {
+				t.Fatalf("LastCall() = %v, want args %v", last, tt.last)
+			}
+
+			for i := range tt.last {
+				if last.Args[i] != tt.last[i] {
+					t.Errorf("argument %d = %v, want %v", i, last.Args[i], tt.last[i])
+				}
+			}
+		})
+	}
+
+	if n := len(m.Calls()); n != 3 {
+		t.Errorf("%d calls, want 3", n)
+	}
+
+	m.Reset()
+
+	if n := len(m.Calls()); n != 0 {
+		t.Errorf("%d calls after Reset(), want 0", n)
+	}
+}
@@ -0,0 +1,188 @@
+package yandextest
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+type WebhookDelivery struct {
+	// Duplicate sends every notification twice.
+	Duplicate bool
+
+	// OutOfOrder sends notifications newest first. Without Hold a
+	// notification is held back until the next one is sent, so even
+	// events of separate requests arrive swapped, FlushWebhooks sends a
+	// notification that is still held back.
+	OutOfOrder bool
+
+	// Hold keeps notifications queued until FlushWebhooks is called.
+	Hold bool
+}
+
+type Delivery struct {
+	Url    string
+	Event  string
+	Status int
+	Err    error
+}
+
+type notification struct {
+	Url  string
+	Body *yandex.Notification
+}
+
+func (s *Server) SetWebhookDelivery(d *WebhookDelivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delivery = *d
+}
+
+func (s *Server) FlushWebhooks() {
+	s.mu.Lock()
+	batch := s.takeOutbox(true)
+	s.mu.Unlock()
+
+	if len(batch) > 0 {
+		s.queue <- batch
+	}
+
+	s.wg.Wait()
+}
+
+func (s *Server) WaitWebhooks() {
+	s.wg.Wait()
+}
+
+func (s *Server) Deliveries() []*Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Delivery(nil), s.deliveries...)
+}
+
+func (s *Server) notify(event string, obj interface{}) {
+	b, err := json.Marshal(obj)
+
+	if err != nil {
+		log.Printf("Failed marshaling struct to bytes: %v\n", err)
+		return
+	}
+
+	for _, wh := range s.webhooks {
+		if wh.Event != event {
+			continue
+		}
+
+		s.outbox = append(s.outbox, &notification{
+			Url: wh.Url,
+			Body: &yandex.Notification{
+				Type:   "notification",
+				Event:  event,
+				Object: b,
+			},
+		})
+	}
+}
+
+func (s *Server) dispatch() {
+	s.mu.Lock()
+
+	if s.delivery.Hold {
+		s.mu.Unlock()
+		return
+	}
+
+	batch := s.takeOutbox(false)
+	s.mu.Unlock()
+
+	if len(batch) > 0 {
+		s.queue <- batch
+	}
+}
+
+// takeOutbox is called with s.mu held, the returned batch is counted in
+// s.wg and must be sent to s.queue.
+func (s *Server) takeOutbox(flush bool) []*notification {
+	batch := s.outbox
+	s.outbox = nil
+
+	if s.closed {
+		s.delayed = nil
+		return nil
+	}
+
+	if s.delivery.OutOfOrder {
+		for i, j := 0, len(batch)-1; i < j; i, j = i+1, j-1 {
+			batch[i], batch[j] = batch[j], batch[i]
+		}
+	}
+
+	batch = append(batch, s.delayed...)
+	s.delayed = nil
+
+	if s.delivery.OutOfOrder && !flush && len(batch) == 1 {
+		s.delayed = batch
+		return nil
+	}
+
+	if s.delivery.Duplicate {
+		res := make([]*notification, 0, len(batch)*2)
+
+		for _, n := range batch {
+			res = append(res, n, n)
+		}
+
+		batch = res
+	}
+
+	if len(batch) > 0 {
+		s.wg.Add(1)
+	}
+
+	return batch
+}
+
+func (s *Server) deliver() {
+	c := &http.Client{Timeout: 10 * time.Second}
+
+	for {
+		var batch []*notification
+
+		select {
+		case batch = <-s.queue:
+		case <-s.done:
+			return
+		}
+
+		for _, n := range batch {
+			d := &Delivery{
+				Url:   n.Url,
+				Event: n.Body.Event,
+			}
+
+			b, err := json.Marshal(n.Body)
+
+			if err != nil {
+				log.Printf("Failed marshaling struct to bytes: %v\n", err)
+				d.Err = err
+			} else if res, err := c.Post(n.Url, "application/json", bytes.NewReader(b)); err != nil {
+				d.Err = err
+			} else {
+				d.Status = res.StatusCode
+				res.Body.Close()
+			}
+
+			s.mu.Lock()
+			s.deliveries = append(s.deliveries, d)
+			s.mu.Unlock()
+		}
+
+		s.wg.Done()
+	}
+}
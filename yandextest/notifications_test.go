@@ -0,0 +1,169 @@
+package yandextest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	payments []string
+}
+
+func newReceiver(t *testing.T, s *Server, events ...string) *receiver {
+	rcv := &receiver{}
+
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		n, err := yandex.ParseNotification(body)
+
+		if err != nil {
+			t.Errorf("ParseNotification() = %v", err)
+			return
+		}
+
+		p, err := n.Payment()
+
+		if err != nil {
+			t.Errorf("Payment() = %v", err)
+			return
+		}
+
+		rcv.mu.Lock()
+		rcv.payments = append(rcv.payments, p.Id)
+		rcv.mu.Unlock()
+	}))
+
+	for _, e := range events {
+		if _, err := s.Client().SubscribeToWebhook(newId(""), &yandex.Webhook{Event: e, Url: rcv.URL}); err != nil {
+			t.Fatalf("SubscribeToWebhook() = %v", err)
+		}
+	}
+
+	return rcv
+}
+
+func (rcv *receiver) received() []string {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	return append([]string(nil), rcv.payments...)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery *WebhookDelivery
+		sent     []int
+		flushed  []int
+	}{
+		{"immediate", &WebhookDelivery{}, []int{0, 1, 2}, []int{0, 1, 2}},
+		{"duplicate", &WebhookDelivery{Duplicate: true}, []int{0, 0, 1, 1, 2, 2}, []int{0, 0, 1, 1, 2, 2}},
+		{"hold", &WebhookDelivery{Hold: true}, nil, []int{0, 1, 2}},
+		{"hold out of order", &WebhookDelivery{Hold: true, OutOfOrder: true}, nil, []int{2, 1, 0}},
+		{"out of order", &WebhookDelivery{OutOfOrder: true}, []int{1, 0}, []int{1, 0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+
+			rcv := newReceiver(t, s, yandex.EventPaymentSucceeded)
+			defer rcv.Close()
+
+			s.SetWebhookDelivery(tt.delivery)
+
+			ids := []string{}
+
+			for i := 0; i < 3; i++ {
+				p, err := payWithCard(s.Client(), CardSuccess)
+
+				if err != nil {
+					t.Fatalf("CreatePayment() = %v", err)
+				}
+
+				ids = append(ids, p.Id)
+			}
+
+			s.WaitWebhooks()
+
+			if got, want := rcv.received(), pick(ids, tt.sent); !reflect.DeepEqual(got, want) {
+				t.Errorf("before flush received %v, want %v", got, want)
+			}
+
+			s.FlushWebhooks()
+
+			if got, want := rcv.received(), pick(ids, tt.flushed); !reflect.DeepEqual(got, want) {
+				t.Errorf("after flush received %v, want %v", got, want)
+			}
+
+			if n := len(s.Deliveries()); n != len(tt.flushed) {
+				t.Errorf("%d deliveries, want %d", n, len(tt.flushed))
+			}
+		})
+	}
+}
+
+func TestWebhookAfterClose(t *testing.T) {
+	s := NewServer()
+
+	rcv := newReceiver(t, s, yandex.EventPaymentCanceled)
+	defer rcv.Close()
+
+	p, err := s.Client().CreatePayment(newId(""), newPaymentRequest())
+
+	if err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	s.SetWebhookDelivery(&WebhookDelivery{Hold: true})
+	s.Close()
+	s.Advance(PendingTimeout)
+	s.FlushWebhooks()
+	s.Close()
+
+	if got := rcv.received(); len(got) > 0 {
+		t.Errorf("received %v after Close()", got)
+	}
+
+	if res, _ := s.Payment(p.Id); res.Status != yandex.PaymentStatusCanceled {
+		t.Errorf("payment is %s, want canceled", res.Status)
+	}
+}
+
+func TestWebhookUnsubscribed(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	rcv := newReceiver(t, s, yandex.EventPaymentWaitingForCapture)
+	defer rcv.Close()
+
+	if _, err := payWithCard(s.Client(), CardSuccess); err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	s.WaitWebhooks()
+
+	if got := rcv.received(); len(got) > 0 {
+		t.Errorf("received %v without subscription", got)
+	}
+}
+
+func pick(ids []string, indexes []int) []string {
+	var res []string
+
+	for _, i := range indexes {
+		res = append(res, ids[i])
+	}
+
+	return res
+}
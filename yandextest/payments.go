@@ -0,0 +1,496 @@
+package yandextest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	GatewayId        string        = "100700"
+	CaptureTimeout   time.Duration = 7 * 24 * time.Hour
+	maxDescription   int           = 128
+	maxMetadataItems int           = 16
+)
+
+var currencies = map[string]bool{
+	"RUB": true,
+	"USD": true,
+	"EUR": true,
+	"BYN": true,
+	"CNY": true,
+	"KZT": true,
+	"UAH": true,
+	"UZS": true,
+}
+
+var confirmationTypes = map[string]bool{
+	"redirect":           true,
+	"embedded":           true,
+	"qr":                 true,
+	"external":           true,
+	"mobile_application": true,
+}
+
+type payment struct {
+	*yandex.Payment
+	Capture    bool
+	Save       bool
+	CardNumber string
+	MethodId   string
+	Created    time.Time
+	Expires    time.Time
+}
+
+func (s *Server) Payment(id string) (*yandex.Payment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[id]
+
+	if !ok {
+		return nil, false
+	}
+
+	res := *p.Payment
+
+	return &res, true
+}
+
+func (s *Server) AuthorizePayment(id string) error {
+	defer s.dispatch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[id]
+
+	if !ok {
+		return fmt.Errorf("payment %s not found", id)
+	}
+
+	if p.Status != "pending" {
+		return fmt.Errorf("payment %s is in %s status", id, p.Status)
+	}
+
+	s.authorizePayment(p)
+
+	return nil
+}
+
+func (s *Server) createPayment(body []byte) (int, interface{}, *apiError) {
+	req := &yandex.PaymentRequest{}
+
+	if e := decodeBody(body, req); e != nil {
+		return 0, nil, e
+	}
+
+	if e := validateAmount(req.Amount, "amount"); e != nil {
+		return 0, nil, e
+	}
+
+	if len(req.Description) > maxDescription {
+		return 0, nil, invalidRequest("Description is too long", "description")
+	}
+
+	if len(req.Metadata) > maxMetadataItems {
+		return 0, nil, invalidRequest("Too many metadata keys", "metadata")
+	}
+
+	sources := 0
+
+	if len(req.PaymentToken) > 0 {
+		sources++
+	}
+
+	if len(req.PaymentMethodId) > 0 {
+		sources++
+	}
+
+	if req.PaymentMethodData != nil {
+		sources++
+	}
+
+	if sources > 1 {
+		return 0, nil, invalidRequest("Only one of payment_token, payment_method_id and payment_method_data can be specified", "payment_method_data")
+	}
+
+	if req.Confirmation != nil {
+		if !confirmationTypes[req.Confirmation.Type] {
+			return 0, nil, invalidRequest("Unknown confirmation type", "confirmation.type")
+		}
+
+		if req.Confirmation.Type == "redirect" && len(req.Confirmation.ReturnUrl) == 0 {
+			return 0, nil, invalidRequest("Return URL isn't specified", "confirmation.return_url")
+		}
+	} else if sources == 0 {
+		return 0, nil, invalidRequest("Confirmation isn't specified", "confirmation")
+	}
+
+	if req.PaymentMethodData != nil && len(req.PaymentMethodData.Type) == 0 {
+		return 0, nil, invalidRequest("Payment method type isn't specified", "payment_method_data.type")
+	}
+
+	if len(req.Transfers) > 0 {
+		total := decimal.Zero
+
+		for _, t := range req.Transfers {
+			if e := validateAmount(t.Amount, "transfers.amount"); e != nil {
+				return 0, nil, e
+			}
+
+			total = total.Add(t.Amount.Value)
+		}
+
+		if !total.Equal(req.Amount.Value) {
+			return 0, nil, invalidRequest("Sum of transfers doesn't match payment amount", "transfers")
+		}
+	}
+
+	id := newId("")
+	now := s.now()
+
+	p := &payment{
+		Payment: &yandex.Payment{
+			Id:          id,
+			Status:      "pending",
+			Amount:      copyAmount(req.Amount),
+			Description: req.Description,
+			Recipient: &yandex.Recipient{
+				AccountId: s.ShopId,
+				GatewayId: GatewayId,
+			},
+			PaymentMethod: responseMethod(req.PaymentMethodData),
+			CreatedAt:     formatTime(now),
+			Test:          true,
+			Metadata:      req.Metadata,
+			Transfers:     req.Transfers,
+		},
+		Capture: req.Capture,
+		Save:    req.SavePaymentMethod,
+		Created: now,
+	}
+
+	if req.PaymentMethodData != nil && req.PaymentMethodData.Card != nil {
+		p.CardNumber = req.PaymentMethodData.Card.Number
+	}
+
+	if len(req.PaymentMethodId) > 0 {
+		m, ok := s.methods[req.PaymentMethodId]
+
+		if !ok || m.Status == yandex.SavedPaymentMethodPending {
+			return 0, nil, invalidRequest("Payment method isn't found or isn't saved", "payment_method_id")
+		}
+
+		p.MethodId = m.Id
+		p.PaymentMethod = &yandex.PaymentMethod{
+			Type:  m.Type,
+			Id:    m.Id,
+			Saved: true,
+			Title: m.Title,
+			Card:  m.Card,
+		}
+	}
+
+	if req.Receipt != nil {
+		p.ReceiptRegistration = "pending"
+	}
+
+	for _, t := range p.Transfers {
+		t.Status = "pending"
+	}
+
+	s.payments[id] = p
+
+	if req.Confirmation != nil {
+		p.Confirmation = s.confirmation(id, req.Confirmation)
+	} else {
+		s.authorizePayment(p)
+	}
+
+	return http.StatusOK, p.Payment, nil
+}
+
+func (s *Server) getPayment(id string) (int, interface{}, *apiError) {
+	p, ok := s.payments[id]
+
+	if !ok {
+		return 0, nil, notFound("Payment not found")
+	}
+
+	return http.StatusOK, p.Payment, nil
+}
+
+func (s *Server) capturePayment(id string, body []byte) (int, interface{}, *apiError) {
+	p, ok := s.payments[id]
+
+	if !ok {
+		return 0, nil, notFound("Payment not found")
+	}
+
+	req := &yandex.PaymentConfirmationRequest{}
+
+	if len(body) > 0 {
+		if e := decodeBody(body, req); e != nil {
+			return 0, nil, e
+		}
+	}
+
+	if p.Status == "succeeded" {
+		return http.StatusOK, p.Payment, nil
+	}
+
+	if p.Status != "waiting_for_capture" {
+		return 0, nil, invalidRequest(fmt.Sprintf("Payment is in %s status and can't be captured", p.Status), "")
+	}
+
+	if req.Amount != nil {
+		if e := validateAmount(req.Amount, "amount"); e != nil {
+			return 0, nil, e
+		}
+
+		if req.Amount.Currency != p.Amount.Currency {
+			return 0, nil, invalidRequest("Currency doesn't match payment currency", "amount.currency")
+		}
+
+		if req.Amount.Value.GreaterThan(p.Amount.Value) {
+			return 0, nil, invalidRequest("Capture amount exceeds payment amount", "amount.value")
+		}
+
+		p.Amount = copyAmount(req.Amount)
+	}
+
+	if len(req.Transfers) > 0 {
+		p.Transfers = req.Transfers
+	}
+
+	s.capture(p)
+
+	return http.StatusOK, p.Payment, nil
+}
+
+func (s *Server) cancelPayment(id string) (int, interface{}, *apiError) {
+	p, ok := s.payments[id]
+
+	if !ok {
+		return 0, nil, notFound("Payment not found")
+	}
+
+	if p.Status == "canceled" {
+		return http.StatusOK, p.Payment, nil
+	}
+
+	if p.Status != "waiting_for_capture" {
+		return 0, nil, invalidRequest(fmt.Sprintf("Payment is in %s status and can't be canceled", p.Status), "")
+	}
+
+	s.cancel(p, yandex.CancellationPartyMerchant, yandex.CancellationReasonCanceledByMerchant)
+
+	return http.StatusOK, p.Payment, nil
+}
+
+func (s *Server) authorizePayment(p *payment) {
+	if d, ok := s.declines[p.CardNumber]; ok {
+		s.cancel(p, d.Party, d.Reason)
+		return
+	}
+
+	if m, ok := s.methods[p.MethodId]; ok && m.Status == yandex.SavedPaymentMethodInactive {
+		p.PaymentMethod.Saved = false
+		s.cancel(p, yandex.CancellationPartyYooMoney, yandex.CancellationReasonPermissionRevoked)
+		return
+	}
+
+	if p.PaymentMethod == nil {
+		p.PaymentMethod = responseMethod(&yandex.PaymentMethod{
+			Type: "bank_card",
+			Card: &yandex.Card{
+				Number:      "5555555555554444",
+				ExpiryYear:  "2030",
+				ExpiryMonth: "12",
+			},
+		})
+	}
+
+	if p.Save {
+		p.PaymentMethod.Saved = true
+		s.savePaymentMethod(p)
+	}
+
+	p.Paid = true
+	p.AuthorizationDetails = &yandex.AuthorizationDetails{
+		RetrievalReferenceNumber: fmt.Sprintf("%012d", s.now().UnixNano()%1000000000000),
+		AuthCode:                 fmt.Sprintf("%06d", s.now().UnixNano()%1000000),
+	}
+
+	if p.Capture {
+		s.capture(p)
+		return
+	}
+
+	p.Status = "waiting_for_capture"
+	p.Expires = s.now().Add(CaptureTimeout)
+	p.ExpiresAt = formatTime(p.Expires)
+
+	for _, t := range p.Transfers {
+		t.Status = "waiting_for_capture"
+	}
+
+	s.notify(yandex.EventPaymentWaitingForCapture, p.Payment)
+}
+
+func (s *Server) capture(p *payment) {
+	p.Status = "succeeded"
+	p.Paid = true
+	p.Refundable = true
+	p.ExpiresAt = ""
+	p.CapturedAt = s.timestamp()
+	p.IncomeMmount = copyAmount(p.Amount)
+	p.RefundedAmount = &yandex.Amount{
+		Value:    decimal.Zero,
+		Currency: p.Amount.Currency,
+	}
+
+	if p.ReceiptRegistration == "pending" {
+		p.ReceiptRegistration = "succeeded"
+	}
+
+	for _, t := range p.Transfers {
+		t.Status = "succeeded"
+	}
+
+	s.notify(yandex.EventPaymentSucceeded, p.Payment)
+}
+
+func (s *Server) cancel(p *payment, party, reason string) {
+	p.Status = "canceled"
+	p.Paid = false
+	p.Refundable = false
+	p.ExpiresAt = ""
+	p.Confirmation = nil
+	p.CancellationDetails = &yandex.CancellationDetails{
+		Party:  party,
+		Reason: reason,
+	}
+
+	if p.ReceiptRegistration == "pending" {
+		p.ReceiptRegistration = "canceled"
+	}
+
+	for _, t := range p.Transfers {
+		t.Status = "canceled"
+	}
+
+	s.notify(yandex.EventPaymentCanceled, p.Payment)
+}
+
+func (s *Server) confirmation(id string, req *yandex.Confirmation) *yandex.Confirmation {
+	c := &yandex.Confirmation{
+		Type:   req.Type,
+		Locale: req.Locale,
+	}
+
+	switch req.Type {
+	case "redirect":
+		c.Enforce = req.Enforce
+		c.ReturnUrl = req.ReturnUrl
+		c.ConfirmationUrl = s.URL + "/checkout/payments/v2/contract?orderId=" + id
+	case "mobile_application":
+		c.ReturnUrl = req.ReturnUrl
+		c.ConfirmationUrl = s.URL + "/checkout/payments/v2/mobile?orderId=" + id
+	case "embedded":
+		c.ConfirmationToken = newId("ct-")
+	case "qr":
+		c.ConfirmationData = "https://qr.nspk.ru/" + id + "?type=02&bank=100000000022&crc=F2A1"
+	}
+
+	return c
+}
+
+func responseMethod(m *yandex.PaymentMethod) *yandex.PaymentMethod {
+	if m == nil {
+		return nil
+	}
+
+	res := &yandex.PaymentMethod{
+		Type:                m.Type,
+		Id:                  newId(""),
+		Login:               m.Login,
+		Phone:               m.Phone,
+		PaymentPurpose:      m.PaymentPurpose,
+		VATData:             m.VATData,
+		PayerBankDetails:    m.PayerBankDetails,
+		SBPPayerBankDetails: m.SBPPayerBankDetails,
+		AccountNumber:       m.AccountNumber,
+	}
+
+	if m.Card != nil {
+		res.Card = &yandex.Card{
+			ExpiryYear:    m.Card.ExpiryYear,
+			ExpiryMonth:   m.Card.ExpiryMonth,
+			CardType:      cardType(m.Card.Number),
+			IssuerCountry: "RU",
+		}
+
+		if len(m.Card.Number) >= 10 {
+			res.Card.BIN = m.Card.Number[:6]
+			res.Card.LastFourDigits = m.Card.Number[len(m.Card.Number)-4:]
+			res.Title = "Bank card *" + res.Card.LastFourDigits
+		}
+	}
+
+	return res
+}
+
+func cardType(number string) string {
+	if len(number) == 0 {
+		return "Unknown"
+	}
+
+	switch number[0] {
+	case '2':
+		return "Mir"
+	case '3':
+		return "AmericanExpress"
+	case '4':
+		return "Visa"
+	case '5':
+		return "MasterCard"
+	}
+
+	return "Unknown"
+}
+
+func validateAmount(a *yandex.Amount, param string) *apiError {
+	if a == nil {
+		return invalidRequest("Amount isn't specified", param)
+	}
+
+	if !a.Value.IsPositive() {
+		return invalidRequest("Amount value must be greater than zero", param+".value")
+	}
+
+	if !a.Value.Round(2).Equal(a.Value) {
+		return invalidRequest("Amount value must have at most two decimal places", param+".value")
+	}
+
+	if !currencies[a.Currency] {
+		return invalidRequest("Unsupported currency", param+".currency")
+	}
+
+	return nil
+}
+
+func copyAmount(a *yandex.Amount) *yandex.Amount {
+	if a == nil {
+		return nil
+	}
+
+	return &yandex.Amount{
+		Value:    a.Value,
+		Currency: a.Currency,
+	}
+}
@@ -0,0 +1,94 @@
+package yandextest
+
+import (
+	"net/http"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func (s *Server) createReceipt(body []byte) (int, interface{}, *apiError) {
+	req := &yandex.ReceiptRequest{}
+
+	if e := decodeBody(body, req); e != nil {
+		return 0, nil, e
+	}
+
+	switch req.Type {
+	case yandex.ReceiptTypePayment:
+		if _, ok := s.payments[req.PaymentId]; !ok {
+			return 0, nil, invalidRequest("Payment not found", "payment_id")
+		}
+	case yandex.ReceiptTypeRefund:
+		if _, ok := s.refunds[req.RefundId]; !ok {
+			return 0, nil, invalidRequest("Refund not found", "refund_id")
+		}
+	default:
+		return 0, nil, invalidRequest("Unknown receipt type", "type")
+	}
+
+	if req.Customer == nil || (len(req.Customer.Email) == 0 && len(req.Customer.Phone) == 0) {
+		return 0, nil, invalidRequest("Customer email or phone isn't specified", "customer")
+	}
+
+	if len(req.Items) == 0 {
+		return 0, nil, invalidRequest("Receipt items aren't specified", "items")
+	}
+
+	for _, item := range req.Items {
+		if len(item.Description) == 0 {
+			return 0, nil, invalidRequest("Item description isn't specified", "items.description")
+		}
+
+		if !item.Quantity.IsPositive() {
+			return 0, nil, invalidRequest("Item quantity must be greater than zero", "items.quantity")
+		}
+
+		if e := validateAmount(item.Amount, "items.amount"); e != nil {
+			return 0, nil, e
+		}
+	}
+
+	if len(req.Settlements) == 0 {
+		return 0, nil, invalidRequest("Settlements aren't specified", "settlements")
+	}
+
+	if !req.Send {
+		return 0, nil, invalidRequest("Only receipts with send=true are supported", "send")
+	}
+
+	receipt := &yandex.Receipt{
+		Id:            newId("rt-"),
+		Type:          req.Type,
+		PaymentId:     req.PaymentId,
+		RefundId:      req.RefundId,
+		Status:        yandex.ReceiptStatusPending,
+		Settlements:   req.Settlements,
+		Customer:      req.Customer,
+		Items:         req.Items,
+		TaxSystemCode: req.TaxSystemCode,
+		OnBehalfOf:    req.OnBehalfOf,
+	}
+
+	s.receipts[receipt.Id] = receipt
+
+	return http.StatusOK, receipt, nil
+}
+
+func (s *Server) getReceipt(id string) (int, interface{}, *apiError) {
+	receipt, ok := s.receipts[id]
+
+	if !ok {
+		return 0, nil, notFound("Receipt not found")
+	}
+
+	if receipt.Status == yandex.ReceiptStatusPending {
+		receipt.Status = yandex.ReceiptStatusSucceeded
+		receipt.RegisteredAt = s.timestamp()
+		receipt.FiscalDocumentNumber = "3986"
+		receipt.FiscalStorageNumber = "9288000100115785"
+		receipt.FiscalAttribute = "2617603921"
+		receipt.FiscalProviderId = "fd9e9404-eaca-4000-8ec9-dc228ead2345"
+	}
+
+	return http.StatusOK, receipt, nil
+}
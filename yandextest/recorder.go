@@ -0,0 +1,264 @@
+package yandextest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/valyala/fasthttp"
+)
+
+type Mode int
+
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+const scrubbed string = "[FILTERED]"
+
+var ScrubbedFields = map[string]bool{
+	"number":               true,
+	"csc":                  true,
+	"cardholder":           true,
+	"payment_token":        true,
+	"payment_method_token": true,
+	"payment_data":         true,
+}
+
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+type Interaction struct {
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Recorder struct {
+	Mode Mode
+	File string
+	Next yandex.Doer
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	misses   []*RecordedRequest
+}
+
+var _ yandex.Doer = (*Recorder)(nil)
+
+func NewRecorder(file string, mode Mode, next yandex.Doer) (*Recorder, error) {
+	r := &Recorder{
+		Mode:     mode,
+		File:     file,
+		Next:     next,
+		cassette: &Cassette{},
+	}
+
+	if mode == ModeRecord {
+		if r.Next == nil {
+			r.Next = &fasthttp.Client{}
+		}
+
+		return r, nil
+	}
+
+	bytes, err := ioutil.ReadFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(bytes, r.cassette); err != nil {
+		log.Printf("Failed unmarshaling bytes to struct: %v\n", err)
+		return nil, err
+	}
+
+	for _, it := range r.cassette.Interactions {
+		it.Request.Body = canonicalBody(it.Request.Body)
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+func (r *Recorder) Do(req *fasthttp.Request, res *fasthttp.Response) error {
+	rr := &RecordedRequest{
+		Method: string(req.Header.Method()),
+		Path:   string(req.URI().Path()),
+		Query:  string(req.URI().QueryString()),
+	}
+
+	if body := req.Body(); json.Valid(body) {
+		rr.Body = canonicalBody(body)
+	} else {
+		rr.Text = string(body)
+	}
+
+	if r.Mode == ModeRecord {
+		return r.record(rr, req, res)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, it := range r.cassette.Interactions {
+		if r.used[i] || !it.Request.matches(rr) {
+			continue
+		}
+
+		r.used[i] = true
+
+		res.SetStatusCode(it.Response.Status)
+		res.Header.SetContentType("application/json")
+
+		if len(it.Response.Body) > 0 {
+			res.SetBody(it.Response.Body)
+		} else {
+			res.SetBodyString(it.Response.Text)
+		}
+
+		return nil
+	}
+
+	r.misses = append(r.misses, rr)
+
+	return fmt.Errorf("yandextest: no recording in %s for %s %s with body %s%s", r.File, rr.Method, rr.Path, string(rr.Body), rr.Text)
+}
+
+func (r *Recorder) record(rr *RecordedRequest, req *fasthttp.Request, res *fasthttp.Response) error {
+	if err := r.Next.Do(req, res); err != nil {
+		return err
+	}
+
+	resp := &RecordedResponse{
+		Status: res.StatusCode(),
+	}
+
+	if body := res.Body(); json.Valid(body) {
+		resp.Body = canonicalBody(body)
+	} else {
+		resp.Text = string(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  rr,
+		Response: resp,
+	})
+
+	return nil
+}
+
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bytes, err := json.MarshalIndent(r.cassette, "", "  ")
+
+	if err != nil {
+		log.Printf("Failed marshaling struct to bytes: %v\n", err)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.File), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.File, append(bytes, '\n'), 0644)
+}
+
+func (r *Recorder) Check() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.misses) > 0 {
+		lines := []string{}
+
+		for _, m := range r.misses {
+			lines = append(lines, m.Method+" "+m.Path)
+		}
+
+		return fmt.Errorf("yandextest: %d requests without recording in %s: %s", len(r.misses), r.File, strings.Join(lines, ", "))
+	}
+
+	for i, used := range r.used {
+		if !used {
+			it := r.cassette.Interactions[i]
+			return fmt.Errorf("yandextest: recording %s %s in %s was not replayed", it.Request.Method, it.Request.Path, r.File)
+		}
+	}
+
+	return nil
+}
+
+func (rr *RecordedRequest) matches(other *RecordedRequest) bool {
+	return rr.Method == other.Method && rr.Path == other.Path && rr.Query == other.Query && string(rr.Body) == string(other.Body) && rr.Text == other.Text
+}
+
+func canonicalBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var v interface{}
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+
+	bytes, err := json.Marshal(scrub(v))
+
+	if err != nil {
+		return nil
+	}
+
+	return bytes
+}
+
+func scrub(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if ScrubbedFields[k] {
+				t[k] = scrubbed
+			} else {
+				t[k] = scrub(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = scrub(val)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,174 @@
+package yandextest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pantuchy/yandex-go"
+	"github.com/valyala/fasthttp"
+)
+
+func tempCassette(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "yandextest")
+
+	if err != nil {
+		t.Fatalf("TempDir() = %v", err)
+	}
+
+	return filepath.Join(dir, "cassettes", "payments.json"), func() { os.RemoveAll(dir) }
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	file, cleanup := tempCassette(t)
+	defer cleanup()
+
+	s := NewServer()
+	defer s.Close()
+
+	rec, err := NewRecorder(file, ModeRecord, nil)
+
+	if err != nil {
+		t.Fatalf("NewRecorder() = %v", err)
+	}
+
+	c := s.Client()
+	c.HttpClient = rec
+
+	created, err := payWithCard(c, CardSuccess)
+
+	if err != nil {
+		t.Fatalf("CreatePayment() = %v", err)
+	}
+
+	if _, err := c.GetPaymentInfo("unknown"); err == nil {
+		t.Fatal("GetPaymentInfo() of unknown payment succeeded")
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	bytes, err := ioutil.ReadFile(file)
+
+	if err != nil {
+		t.Fatalf("ReadFile() = %v", err)
+	}
+
+	if strings.Contains(string(bytes), CardSuccess) {
+		t.Error("card number isn't scrubbed from the cassette")
+	}
+
+	replay, err := NewRecorder(file, ModeReplay, nil)
+
+	if err != nil {
+		t.Fatalf("NewRecorder() = %v", err)
+	}
+
+	c = &yandex.Yandex{ShopId: "replay", SecretKey: "replay", BaseURL: "http://localhost", HttpClient: replay}
+
+	p, err := payWithCard(c, CardSuccess)
+
+	if err != nil || p.Id != created.Id {
+		t.Fatalf("replayed CreatePayment() = %v, %v", p, err)
+	}
+
+	if _, err := c.GetPaymentInfo("unknown"); err == nil {
+		t.Error("replayed GetPaymentInfo() of unknown payment succeeded")
+	}
+
+	if err := replay.Check(); err != nil {
+		t.Errorf("Check() = %v", err)
+	}
+
+	if _, err := c.GetPaymentInfo("unknown"); err == nil {
+		t.Error("recording was replayed twice")
+	}
+
+	if err := replay.Check(); err == nil {
+		t.Error("Check() after miss succeeded")
+	}
+}
+
+func TestRecorderMatching(t *testing.T) {
+	file, cleanup := tempCassette(t)
+	defer cleanup()
+
+	cassette := &Cassette{
+		Interactions: []*Interaction{
+			{
+				Request:  &RecordedRequest{Method: "POST", Path: "/upload", Text: "first"},
+				Response: &RecordedResponse{Status: 200, Text: "1"},
+			},
+			{
+				Request:  &RecordedRequest{Method: "POST", Path: "/upload", Text: "second"},
+				Response: &RecordedResponse{Status: 200, Text: "2"},
+			},
+			{
+				Request:  &RecordedRequest{Method: "POST", Path: "/payments", Body: json.RawMessage(`{"b":2,"a":1}`)},
+				Response: &RecordedResponse{Status: 200, Body: json.RawMessage(`{"id":"p1"}`)},
+			},
+		},
+	}
+
+	bytes, _ := json.Marshal(cassette)
+	os.MkdirAll(filepath.Dir(file), 0755)
+
+	if err := ioutil.WriteFile(file, bytes, 0644); err != nil {
+		t.Fatalf("WriteFile() = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		res  string
+	}{
+		{"text body", "/upload", "second", "2"},
+		{"unknown text body", "/upload", "third", ""},
+		{"other text body", "/upload", "first", "1"},
+		{"json in other key order", "/payments", `{"a": 1, "b": 2}`, `{"id":"p1"}`},
+		{"other json", "/payments", `{"a": 2}`, ""},
+	}
+
+	r, err := NewRecorder(file, ModeReplay, nil)
+
+	if err != nil {
+		t.Fatalf("NewRecorder() = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := fasthttp.AcquireRequest()
+			res := fasthttp.AcquireResponse()
+
+			defer fasthttp.ReleaseRequest(req)
+			defer fasthttp.ReleaseResponse(res)
+
+			req.Header.SetMethod("POST")
+			req.SetRequestURI("http://localhost" + tt.path)
+			req.SetBodyString(tt.body)
+
+			err := r.Do(req, res)
+
+			if len(tt.res) == 0 {
+				if err == nil {
+					t.Errorf("Do() replayed %s", res.Body())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Do() = %v", err)
+			}
+
+			if string(res.Body()) != tt.res {
+				t.Errorf("Do() replayed %s, want %s", res.Body(), tt.res)
+			}
+		})
+	}
+}
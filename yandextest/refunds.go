@@ -0,0 +1,71 @@
+package yandextest
+
+import (
+	"net/http"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+func (s *Server) createRefund(body []byte) (int, interface{}, *apiError) {
+	req := &yandex.RefundRequest{}
+
+	if e := decodeBody(body, req); e != nil {
+		return 0, nil, e
+	}
+
+	if len(req.PaymentId) == 0 {
+		return 0, nil, invalidRequest("Payment id isn't specified", "payment_id")
+	}
+
+	p, ok := s.payments[req.PaymentId]
+
+	if !ok {
+		return 0, nil, invalidRequest("Payment not found", "payment_id")
+	}
+
+	if e := validateAmount(req.Amount, "amount"); e != nil {
+		return 0, nil, e
+	}
+
+	if p.Status != "succeeded" || !p.Refundable {
+		return 0, nil, invalidRequest("Payment can't be refunded", "payment_id")
+	}
+
+	if req.Amount.Currency != p.Amount.Currency {
+		return 0, nil, invalidRequest("Currency doesn't match payment currency", "amount.currency")
+	}
+
+	refunded := req.Amount.Value.Add(p.RefundedAmount.Value)
+
+	if refunded.GreaterThan(p.Amount.Value) {
+		return 0, nil, invalidRequest("Refund amount exceeds the rest of payment amount", "amount.value")
+	}
+
+	refund := &yandex.Refund{
+		Id:          newId(""),
+		PaymentId:   p.Id,
+		Status:      "succeeded",
+		CreatedAt:   s.timestamp(),
+		Amount:      copyAmount(req.Amount),
+		Description: req.Description,
+		Sources:     req.Sources,
+	}
+
+	p.RefundedAmount.Value = refunded
+	p.Refundable = refunded.LessThan(p.Amount.Value)
+
+	s.refunds[refund.Id] = refund
+	s.notify(yandex.EventRefundSucceeded, refund)
+
+	return http.StatusOK, refund, nil
+}
+
+func (s *Server) getRefund(id string) (int, interface{}, *apiError) {
+	refund, ok := s.refunds[id]
+
+	if !ok {
+		return 0, nil, notFound("Refund not found")
+	}
+
+	return http.StatusOK, refund, nil
+}
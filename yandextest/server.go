@@ -0,0 +1,371 @@
+package yandextest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pantuchy/yandex-go"
+)
+
+const (
+	DefaultShopId     string = "100500"
+	DefaultSecretKey  string = "test_secret_key"
+	DefaultOAuthToken string = "test_oauth_token"
+)
+
+type Server struct {
+	URL        string
+	ShopId     string
+	SecretKey  string
+	OAuthToken string
+	Store      *yandex.Store
+
+	srv         *httptest.Server
+	mu          sync.Mutex
+	payments    map[string]*payment
+	refunds     map[string]*yandex.Refund
+	receipts    map[string]*yandex.Receipt
+	webhooks    map[string]*yandex.Webhook
+	methods     map[string]*yandex.SavedPaymentMethod
+	idempotence map[string]*idempotentResponse
+	declines    map[string]*yandex.CancellationDetails
+	faults      []*activeFault
+	retries     map[string]int
+	offset      time.Duration
+	delivery    WebhookDelivery
+	outbox      []*notification
+	delayed     []*notification
+	deliveries  []*Delivery
+	queue       chan []*notification
+	done        chan struct{}
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+type idempotentResponse struct {
+	Method string
+	Path   string
+	Body   []byte
+	Status int
+	Result []byte
+}
+
+type apiError struct {
+	Status      int
+	Code        string
+	Description string
+	Parameter   string
+}
+
+func NewServer() *Server {
+	s := &Server{
+		ShopId:      DefaultShopId,
+		SecretKey:   DefaultSecretKey,
+		OAuthToken:  DefaultOAuthToken,
+		payments:    map[string]*payment{},
+		refunds:     map[string]*yandex.Refund{},
+		receipts:    map[string]*yandex.Receipt{},
+		webhooks:    map[string]*yandex.Webhook{},
+		methods:     map[string]*yandex.SavedPaymentMethod{},
+		idempotence: map[string]*idempotentResponse{},
+		declines:    defaultDeclines(),
+		retries:     map[string]int{},
+		queue:       make(chan []*notification, 1024),
+		done:        make(chan struct{}),
+	}
+
+	s.Store = &yandex.Store{
+		AccountId:            s.ShopId,
+		Test:                 true,
+		FiscalizationEnabled: true,
+		PaymentMethods:       []string{"bank_card", "yoo_money", "sberbank", "sbp", "tinkoff_bank"},
+	}
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+
+	go s.deliver()
+
+	return s
+}
+
+func (s *Server) Client() *yandex.Yandex {
+	return &yandex.Yandex{
+		ShopId:     s.ShopId,
+		SecretKey:  s.SecretKey,
+		OAuthToken: s.OAuthToken,
+		BaseURL:    s.URL,
+	}
+}
+
+// Close stops the server, notifications of later calls such as Advance
+// are dropped.
+func (s *Server) Close() {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+
+	s.closed = true
+	s.mu.Unlock()
+
+	s.srv.Close()
+	s.wg.Wait()
+	close(s.done)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, "invalid_request", "Failed reading request body", ""})
+		return
+	}
+
+	defer s.dispatch()
+
+	if s.isRetryOfDrop(r, body) {
+		dropConnection(w)
+		return
+	}
+
+	f := s.matchFault(r)
+
+	if f == nil {
+		s.handle(w, r, body)
+		return
+	}
+
+	if f.Latency > 0 {
+		time.Sleep(f.Latency)
+	}
+
+	if !f.Applied {
+		switch {
+		case f.Drop:
+			s.rememberDrop(r, body)
+			dropConnection(w)
+			return
+		case f.Status != 0:
+			writeFault(w, f)
+			return
+		}
+	}
+
+	rec := httptest.NewRecorder()
+
+	s.handle(rec, r, body)
+
+	switch {
+	case f.Drop:
+		s.rememberDrop(r, body)
+		dropConnection(w)
+	case f.Status != 0:
+		writeFault(w, f)
+	case f.Truncate:
+		b := rec.Body.Bytes()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rec.Code)
+		w.Write(b[:len(b)/2])
+	default:
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, body []byte) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	if e := s.authorize(r, parts[0]); e != nil {
+		writeError(w, e)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		s.serveIdempotent(w, r, parts, body)
+		return
+	}
+
+	status, res, e := s.route(r.Method, parts, body)
+
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+
+	writeJSON(w, status, res)
+}
+
+func (s *Server) authorize(r *http.Request, resource string) *apiError {
+	auth := r.Header.Get("Authorization")
+
+	if strings.HasPrefix(auth, "Bearer ") {
+		if strings.TrimPrefix(auth, "Bearer ") == s.OAuthToken {
+			return nil
+		}
+	} else if resource != "webhooks" && auth == "Basic "+base64.StdEncoding.EncodeToString([]byte(s.ShopId+":"+s.SecretKey)) {
+		return nil
+	}
+
+	return &apiError{http.StatusUnauthorized, "invalid_credentials", "Authentication error: check shopId and secret key or OAuth token", ""}
+}
+
+func (s *Server) serveIdempotent(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	key := r.Header.Get("Idempotence-Key")
+
+	if len(key) == 0 {
+		writeError(w, &apiError{http.StatusBadRequest, "invalid_request", "Idempotence key isn't specified", "Idempotence-Key"})
+		return
+	}
+
+	if len(key) > 64 {
+		writeError(w, &apiError{http.StatusBadRequest, "invalid_request", "Idempotence key is too long", "Idempotence-Key"})
+		return
+	}
+
+	if prev, ok := s.idempotence[key]; ok {
+		if prev.Method != r.Method || prev.Path != r.URL.Path || !bytes.Equal(prev.Body, body) {
+			writeError(w, &apiError{http.StatusBadRequest, "invalid_request", "Idempotence key duplicated with another request parameters", "Idempotence-Key"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(prev.Status)
+		w.Write(prev.Result)
+		return
+	}
+
+	status, res, e := s.route(r.Method, parts, body)
+
+	if e != nil {
+		status = e.Status
+		res = newErrorResponse(e)
+	}
+
+	b, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Failed marshaling struct to bytes: %v\n", err)
+		writeError(w, &apiError{http.StatusInternalServerError, "internal_server_error", err.Error(), ""})
+		return
+	}
+
+	if status != http.StatusInternalServerError {
+		s.idempotence[key] = &idempotentResponse{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Body:   body,
+			Status: status,
+			Result: b,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func (s *Server) route(method string, parts []string, body []byte) (int, interface{}, *apiError) {
+	switch {
+	case method == http.MethodPost && len(parts) == 1 && parts[0] == "payments":
+		return s.createPayment(body)
+	case method == http.MethodGet && len(parts) == 2 && parts[0] == "payments":
+		return s.getPayment(parts[1])
+	case method == http.MethodPost && len(parts) == 3 && parts[0] == "payments" && parts[2] == "capture":
+		return s.capturePayment(parts[1], body)
+	case method == http.MethodPost && len(parts) == 3 && parts[0] == "payments" && parts[2] == "cancel":
+		return s.cancelPayment(parts[1])
+	case method == http.MethodPost && len(parts) == 1 && parts[0] == "payment_methods":
+		return s.createPaymentMethod(body)
+	case method == http.MethodGet && len(parts) == 2 && parts[0] == "payment_methods":
+		return s.getPaymentMethod(parts[1])
+	case method == http.MethodPost && len(parts) == 1 && parts[0] == "refunds":
+		return s.createRefund(body)
+	case method == http.MethodGet && len(parts) == 2 && parts[0] == "refunds":
+		return s.getRefund(parts[1])
+	case method == http.MethodPost && len(parts) == 1 && parts[0] == "receipts":
+		return s.createReceipt(body)
+	case method == http.MethodGet && len(parts) == 2 && parts[0] == "receipts":
+		return s.getReceipt(parts[1])
+	case method == http.MethodPost && len(parts) == 1 && parts[0] == "webhooks":
+		return s.createWebhook(body)
+	case method == http.MethodGet && len(parts) == 1 && parts[0] == "webhooks":
+		return s.listWebhooks()
+	case method == http.MethodDelete && len(parts) == 2 && parts[0] == "webhooks":
+		return s.deleteWebhook(parts[1])
+	case method == http.MethodGet && len(parts) == 1 && parts[0] == "me":
+		return http.StatusOK, s.Store, nil
+	}
+
+	return 0, nil, &apiError{http.StatusNotFound, "not_found", "Resource not found", ""}
+}
+
+func decodeBody(body []byte, v interface{}) *apiError {
+	if err := json.Unmarshal(body, v); err != nil {
+		return &apiError{http.StatusBadRequest, "invalid_request", "Request body is not a valid JSON: " + err.Error(), ""}
+	}
+
+	return nil
+}
+
+func newId(prefix string) string {
+	return prefix + uuid.Must(uuid.NewV4()).String()
+}
+
+func newErrorResponse(e *apiError) *yandex.ErrorResponse {
+	return &yandex.ErrorResponse{
+		Type:        "error",
+		Id:          newId(""),
+		Code:        e.Code,
+		Description: e.Description,
+		Parameter:   e.Parameter,
+	}
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	writeJSON(w, e.Status, newErrorResponse(e))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		log.Printf("Failed marshaling struct to bytes: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func invalidRequest(description, parameter string) *apiError {
+	return &apiError{http.StatusBadRequest, "invalid_request", description, parameter}
+}
+
+func notFound(description string) *apiError {
+	return &apiError{http.StatusNotFound, "not_found", description, ""}
+}
@@ -0,0 +1,74 @@
+package yandextest
+
+import (
+	"net/http"
+
+	"github.com/pantuchy/yandex-go"
+)
+
+var events = map[string]bool{
+	yandex.EventPaymentWaitingForCapture: true,
+	yandex.EventPaymentSucceeded:         true,
+	yandex.EventPaymentCanceled:          true,
+	yandex.EventRefundSucceeded:          true,
+	yandex.EventDealClosed:               true,
+	yandex.EventInvoiceSucceeded:         true,
+	yandex.EventInvoiceCanceled:          true,
+	yandex.EventPayoutSucceeded:          true,
+	yandex.EventPayoutCanceled:           true,
+}
+
+func (s *Server) createWebhook(body []byte) (int, interface{}, *apiError) {
+	req := &yandex.Webhook{}
+
+	if e := decodeBody(body, req); e != nil {
+		return 0, nil, e
+	}
+
+	if !events[req.Event] {
+		return 0, nil, invalidRequest("Unknown event", "event")
+	}
+
+	if len(req.Url) == 0 {
+		return 0, nil, invalidRequest("URL isn't specified", "url")
+	}
+
+	for _, wh := range s.webhooks {
+		if wh.Event == req.Event && wh.Url == req.Url {
+			return http.StatusOK, wh, nil
+		}
+	}
+
+	wh := &yandex.Webhook{
+		Id:    newId("wh-"),
+		Event: req.Event,
+		Url:   req.Url,
+	}
+
+	s.webhooks[wh.Id] = wh
+
+	return http.StatusOK, wh, nil
+}
+
+func (s *Server) listWebhooks() (int, interface{}, *apiError) {
+	res := &yandex.WebhooksListResponse{
+		Type:  "list",
+		Items: []*yandex.Webhook{},
+	}
+
+	for _, wh := range s.webhooks {
+		res.Items = append(res.Items, wh)
+	}
+
+	return http.StatusOK, res, nil
+}
+
+func (s *Server) deleteWebhook(id string) (int, interface{}, *apiError) {
+	if _, ok := s.webhooks[id]; !ok {
+		return 0, nil, notFound("Webhook not found")
+	}
+
+	delete(s.webhooks, id)
+
+	return http.StatusOK, struct{}{}, nil
+}